@@ -1,16 +1,15 @@
 package simpdf
 
 import (
-	"errors"
 	"fmt"
 	"image/color"
-	"log"
+	"math"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/braddschick/simpdf/internal"
 	"github.com/braddschick/simpdf/pkg/defaults"
 	"github.com/braddschick/simpdf/pkg/models"
 	"github.com/jung-kurt/gofpdf"
@@ -57,24 +56,21 @@ func (s *SimPDF) SetMargin(margin models.Margins) {
 
 // NewFont sets the font to be used if it is not a standard font already provided.
 // This will verify the file exists and if it is a directory the first TTF will be used.
+// An error wrapping ErrFontNotFound is returned if no font can be accessed.
 func (s *SimPDF) NewFont(fontFilePath string) error {
 	var font models.Fonts
 	fileFont, err := os.Stat(fontFilePath)
-	if os.IsNotExist(err) {
-		log.Fatalf("Font file %s cannot be accessed or does not exist.", fontFilePath)
-		panic(err)
+	if err != nil {
+		return fmt.Errorf("%w: %s cannot be accessed or does not exist", ErrFontNotFound, fontFilePath)
 	}
 	if fileFont.IsDir() {
-		log.Print("Font path given was a directory. The first ttf found will be used.")
 		font.Directory = fontFilePath
-		fonts, _ := filepath.Glob(fontFilePath + "*.ttf")
+		fonts, _ := filepath.Glob(filepath.Join(fontFilePath, "*.ttf"))
 		if len(fonts) > 0 {
-			// Need to remove path and only return the filename
-			font.Name = fonts[0]
+			font.Name = filepath.Base(fonts[0])
 			font.IsValid = true
 		} else {
-			err := fmt.Sprintf("Font file(s) %s cannot be accessed or do not exist. No File with an extension of ttf exists.", fontFilePath)
-			return errors.New(err)
+			return fmt.Errorf("%w: no file with an extension of ttf exists in %s", ErrFontNotFound, fontFilePath)
 		}
 	} else {
 		font.Directory = filepath.Dir(fontFilePath)
@@ -114,24 +110,30 @@ func (s *SimPDF) SetPage(pageType string, isLandscape bool) {
 	s.Page.IsLandscape = isLandscape
 }
 
-// StyleName returns the style as directed by the name given. If the name given is not found
-// then "Normal" will be looked for to use in lieu thereof.
+// StyleName returns the style as directed by the name given, matched without regard to case.
+// An empty name is "Normal". If the style does not exist an error wrapping ErrStyleNotFound is
+// returned.
 func (s *SimPDF) StyleName(name string) (models.Styles, error) {
-	var out models.Styles
+	if name == "" {
+		name = "Normal"
+	}
 	for _, t := range s.Style {
-		if strings.EqualFold(t.Name, name) {
-			out = t
-			break
+		if strings.EqualFold(t.Name, name) && t.TextSize != 0 {
+			return t, nil
 		}
 	}
-	if out.Name == "" {
-		out, _ = s.StyleName("Normal")
-	}
-	if out.TextSize == 0 {
-		err := fmt.Sprintf("Could not find the Style %s by name, and the Style 'Normal' does not exist.", name)
-		return models.Styles{}, errors.New(err)
+	return models.Styles{}, fmt.Errorf("%w: %q", ErrStyleNotFound, name)
+}
+
+// style returns the style as directed by SimPDF.StyleName(). Failure to find the style is
+// recorded as the document error and false is returned.
+func (s *SimPDF) style(name string) (models.Styles, bool) {
+	style, err := s.StyleName(name)
+	if err != nil {
+		s.SetError(err)
+		return models.Styles{}, false
 	}
-	return out, nil
+	return style, true
 }
 
 // AddNewLine Adds a new line to the PDF document the same line height as previously used.
@@ -160,6 +162,17 @@ func (s *SimPDF) DrawBottomLine(style models.Styles) {
 	s.PDF.SetDrawColor(r, g, b)
 }
 
+// drawRule draws a line across the page, between the left and right margins, at y.
+func (s *SimPDF) drawRule(y float64, color models.RGBColor, width float64) {
+	r, g, b := s.PDF.GetDrawColor()
+	lw := s.PDF.GetLineWidth()
+	s.PDF.SetDrawColor(int(color.Red), int(color.Green), int(color.Blue))
+	s.PDF.SetLineWidth(width)
+	s.PDF.Line(s.Margin.Left, y, s.Width()-s.Margin.Right, y)
+	s.PDF.SetLineWidth(lw)
+	s.PDF.SetDrawColor(r, g, b)
+}
+
 // BottomLine is the main function for adding a bottom line to the text as directed by the
 // style given.
 func (s *SimPDF) BottomLine(style models.Styles) {
@@ -172,6 +185,7 @@ func (s *SimPDF) BottomLine(style models.Styles) {
 // TL/C/R - Top Left/Center/Right
 // CL/C/R - Center Left/Center/Right
 // BL/C/R - Bottom Left/Center/Right
+// Any other position is recorded as an error wrapping ErrInvalidPosition.
 func (s *SimPDF) StandardPosition(position string) (float64, float64) {
 	// position {string} can be anyone of these values
 	// "tl", "tc", "tr"
@@ -197,7 +211,7 @@ func (s *SimPDF) StandardPosition(position string) (float64, float64) {
 			x = s.Width() - s.Margin.Right
 		}
 	} else {
-		internal.IfError("Not enough was passed for Standard Position", nil, false)
+		s.SetError(fmt.Errorf("%w: %q", ErrInvalidPosition, position))
 	}
 	return x, y
 }
@@ -245,17 +259,41 @@ func (s *SimPDF) StringWidth(text string) float64 {
 }
 
 // CheckBottom ensures that the bottom of the page including the bottom margin is not going to be passed.
+// The footer band is used in lieu of the bottom margin when it is taller.
 //
 // true equals it does pass the bottom of the page.
 // false equals it is not going to pass the bottom of the page.
 func (s *SimPDF) CheckBottom() bool {
-	return (s.PDF.GetY() + s.Margin.Bottom) > s.Height()
+	return (s.PDF.GetY() + math.Max(s.Margin.Bottom, s.footerBand)) > s.Height()
+}
+
+// contentTop returns the Y coordinate the contents of the page start at, below the header band
+// and its separator.
+func (s *SimPDF) contentTop(page int) float64 {
+	header := s.Header.forPage(page)
+	top := s.Margin.Top + header.bandHeight()
+	if _, width := header.separator(); width > 0 {
+		top += width + 4
+	}
+	return top
+}
+
+// pageBottom returns the lowest Y coordinate content may reach on the page, above the bottom
+// margin, the footer band, and the automatic page break of gofpdf.
+func (s *SimPDF) pageBottom() float64 {
+	bottom := s.Height() - math.Max(s.Margin.Bottom, s.footerBand)
+	if auto, margin := s.PDF.GetAutoPageBreak(); auto {
+		bottom = math.Min(bottom, s.Height()-margin)
+	}
+	return bottom
 }
 
 func (s *SimPDF) fontReset(style models.Styles) {
 	if style.Name == "" {
-		sty, err := s.StyleName("Normal")
-		internal.IfError("fontReset private", err, false)
+		sty, ok := s.style("Normal")
+		if !ok {
+			return
+		}
 		s.SetStyle(sty, true)
 	} else {
 		s.SetStyle(style, true)
@@ -271,11 +309,57 @@ func (s *SimPDF) Font(style models.Styles) {
 
 // HeadingStart this function is called before placing a heading into the PDF document.
 // Useful for adding a new line, maybe a bookmark, or anything else.
+// "Title" and "Heading N" styles are added to the PDF outline by SimPDF.AddBookmark() and
+// to the table of contents, if any. A heading whose first line does not fit on the page starts
+// a new page first, so both point at the page the heading is written on.
 func (s *SimPDF) HeadingStart(styleType, text string) {
 	if !strings.Contains(strings.ToLower(styleType), "subtitle") {
 		s.NewLine(0)
-		// s.AddBookmark(styleType, text)
 	}
+	if style, err := s.StyleName(styleType); err == nil && s.PDF.GetY()+style.LineSize > s.pageBottom() {
+		s.Break()
+	}
+	if level, ok := headingLevel(styleType); ok {
+		if level == 0 {
+			s.section = text
+		}
+		s.AddBookmark(text, level)
+		s.addHeading(text, level)
+	}
+}
+
+// headingLevel returns the outline level of a heading style. "Title" and "Heading 1" are
+// level 0, "Heading 2" is level 1, and so on. false is returned for any other style.
+func headingLevel(styleType string) (int, bool) {
+	name := strings.ToLower(strings.TrimSpace(styleType))
+	if name == "title" {
+		return 0, true
+	}
+	if !strings.HasPrefix(name, "heading") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(name, "heading")))
+	if err != nil || n < 1 {
+		return 0, false
+	}
+	return n - 1, true
+}
+
+// AddBookmark adds text to the PDF outline, shown by viewers as bookmarks, pointing at the
+// current position. level 0 is the top level, 1 is just below, and so on. A level deeper
+// than one below the previous bookmark is raised so the outline stays properly nested.
+func (s *SimPDF) AddBookmark(text string, level int) {
+	if s.Err() {
+		return
+	}
+	if level < 0 {
+		level = 0
+	}
+	if level > s.outlineDepth {
+		level = s.outlineDepth
+	}
+	s.PDF.Bookmark(text, level, -1)
+	s.outlineDepth = level + 1
 }
 
 // HeadingEnd this function is called after placing a heading into the PDF document.
@@ -306,6 +390,14 @@ func (s *SimPDF) Box(outline, fill color.Color, x, y, w, h float64) {
 // WriteImageInset Allows for an image to be inset on the top left, tl, or at the top right, tr, as desired.
 func (s *SimPDF) WriteImageInset(styleType string, align models.Alignments, text, imgPosition string, image Images) {
 	pos := strings.Split(strings.ToLower(imgPosition), "")
+	if len(pos) != 2 {
+		s.SetError(fmt.Errorf("%w: %q", ErrInvalidPosition, imgPosition))
+		return
+	}
+	style, ok := s.style(styleType)
+	if !ok {
+		return
+	}
 	var cX float64
 	s.NewLine(-1)
 	iWpt, iHpt := image.PointsSize()
@@ -327,7 +419,6 @@ func (s *SimPDF) WriteImageInset(styleType string, align models.Alignments, text
 	s.Write(styleType, align, text)
 	_, y2 := s.PDF.GetXY()
 	if y+iHpt > y2 {
-		style, _ := s.StyleName(styleType)
 		s.PDF.SetY(y + iHpt + (style.LineSize * 1.5))
 	}
 	s.NewLine(-1)
@@ -340,26 +431,34 @@ func (s *SimPDF) WriteImageInset(styleType string, align models.Alignments, text
 // Writes to the center of the page.
 // align variable is "L" left, "C" center, or "R" right text alignment.
 func (s *SimPDF) WriteCenter(styleType string, align models.Alignments, text string) {
-	style, _ := s.StyleName(styleType)
+	style, ok := s.style(styleType)
+	if !ok {
+		return
+	}
 	y := (s.Height() / 2) - style.LineSize
-	// internal.IfError("WriteCenter public "+err.Error(), err, false)
 	s.PDF.SetY(y)
 	s.Write(styleType, align, text)
 }
 
 // Write Simply writes the contents of the variable text to the PDF document as perscribed by the
 // styleType variable. align variable is "L" left, "C" center, or "R" right text alignment.
+// A style that cannot be found is recorded as the document error and nothing is written.
 func (s *SimPDF) Write(styleType string, align models.Alignments, text string) {
+	if s.Err() {
+		return
+	}
+	style, ok := s.style(styleType)
+	if !ok {
+		return
+	}
 	if s.CheckBottom() {
 		s.Break()
 	}
 	if !strings.Contains(strings.ToLower(styleType), "normal") {
-		s.HeadingStart(styleType, text)
+		s.HeadingStart(styleType, s.inlineText(text))
 	}
-	style, _ := s.StyleName(styleType)
 	clean := s.Parser(styleType, align, text)
 	if len(clean) > 0 {
-		style, _ := s.StyleName(styleType)
 		s.Font(style)
 		s.PDF.WriteAligned(0, style.LineSize, clean, align.ToPDF())
 
@@ -367,9 +466,8 @@ func (s *SimPDF) Write(styleType string, align models.Alignments, text string) {
 	if !strings.Contains(strings.ToLower(styleType), "normal") {
 		s.HeadingEnd(styleType)
 	}
-	reset, _ := s.StyleName("Normal")
 	s.BottomLine(style)
-	s.fontReset(reset)
+	s.fontReset(models.Styles{})
 }
 
 // Page changes the page size to the one set as page variable must be a models.Pages
@@ -380,19 +478,42 @@ func (s *SimPDF) NewPage(page models.Pages) {
 	s.PDF.AddPageFormat(page.ToPDFOrientation(), gofpdf.SizeType{Wd: page.Width, Ht: page.Height})
 }
 
-func (s *SimPDF) SetHeader() {
+// SetHeader sets the header written at the top of every page of this PDF document.
+// Headers belong to the SimPDF, so documents may be built in parallel goroutines.
+// The current page, started without a header, gets the header as well, so the first page
+// created by SimPDF.Start() has it too. Writing continues below the header; set it before
+// writing to the page so nothing is written where the header goes.
+func (s *SimPDF) SetHeader(header HeaderFooters) {
+	s.Header = s.sizeImages(header)
 	s.PDF.SetHeaderFunc(func() {
-		// s.PDF.SetX(s.Margin.Left)
-		// s.PDF.SetY(s.Margin.Top / 2)
-		Header.Write(s)
+		s.Header.Write(s)
+		s.headerPage = s.PDF.PageNo()
 	})
-}
-
-func (s *SimPDF) SetFooter() {
+	if s.headerPage == s.PDF.PageNo() {
+		return
+	}
+	x, y := s.PDF.GetXY()
+	s.PDF.SetY(s.Margin.Top)
+	s.Header.Write(s)
+	s.headerPage = s.PDF.PageNo()
+	if below := s.PDF.GetY(); below > y {
+		x, y = s.Margin.Left, below
+	}
+	s.PDF.SetXY(x, y)
+	s.fontReset(models.Styles{})
+}
+
+// SetFooter sets the footer written at the bottom of every page of this PDF document.
+// Footers belong to the SimPDF, so documents may be built in parallel goroutines.
+func (s *SimPDF) SetFooter(footer HeaderFooters) {
+	s.Footer = s.sizeImages(footer)
+	s.Footer.footer = true
+	s.footerBand = (s.Margin.Bottom / 2) + s.Footer.reserved()
+	if auto, margin := s.PDF.GetAutoPageBreak(); auto && s.footerBand > margin {
+		s.PDF.SetAutoPageBreak(true, s.footerBand)
+	}
 	s.PDF.SetFooterFunc(func() {
-		s.PDF.SetX(s.Margin.Left)
-		s.PDF.SetY(s.Page.Height - s.Margin.Top - (s.Margin.Bottom / 2))
-		Footer.Write(s)
+		s.Footer.Write(s)
 	})
 }
 
@@ -403,25 +524,31 @@ func (s *SimPDF) init(fontDirectory string) {
 		FontDirStr:     fontDirectory,
 		OrientationStr: s.Page.ToPDFOrientation(),
 	})
+	s.PDF.SetError(s.err)
+	s.PDF.AliasNbPages("")
+	s.AddMargins(s.Margin)
 	s.PDF.AddPage()
 }
 
 // Start The main entry function for creating a PDF document.
 // pageType - string sizes
 // isLandscape - true means the page orientation is landscape, false is portrait
-func (s *SimPDF) Start(pageType string, isLandscape bool, styles []models.Styles, margin models.Margins, customFontDirectory string) {
+//
+// An error wrapping ErrStyleNotFound is returned if styles does not contain "Normal", and
+// one wrapping ErrFontNotFound if customFontDirectory is given but is not a directory.
+// The error is also recorded as the document error.
+func (s *SimPDF) Start(pageType string, isLandscape bool, styles []models.Styles, margin models.Margins, customFontDirectory string) error {
 	s.SetPage(pageType, isLandscape)
 	s.Style = styles
 	s.Margin = margin
-	s.init(customFontDirectory)
-	s.AddMargins(s.Margin)
-}
-
-// Finish ends the creation of the PDF document and saves it to a file as prescribed in fileOutput.
-// If a file already exists at fileOutput it will be moved and appened with ".bak" file extention.
-func (s *SimPDF) Finish(fileOutput string) {
-	if internal.ValidateFilePath(fileOutput) {
-		internal.MoveFilePath(fileOutput, fileOutput+".bak")
+	if customFontDirectory != "" {
+		if dir, err := os.Stat(customFontDirectory); err != nil || !dir.IsDir() {
+			s.SetError(fmt.Errorf("%w: font directory %s cannot be accessed", ErrFontNotFound, customFontDirectory))
+		}
+	}
+	if _, err := s.StyleName("Normal"); err != nil {
+		s.SetError(err)
 	}
-	s.PDF.OutputFileAndClose(fileOutput)
+	s.init(customFontDirectory)
+	return s.Error()
 }
@@ -0,0 +1,32 @@
+package simpdf
+
+import (
+	"testing"
+
+	"github.com/braddschick/simpdf/pkg/defaults"
+	"github.com/braddschick/simpdf/pkg/models"
+)
+
+// TestHeadingAtPageBottom checks that a heading that does not fit at the bottom of a page is
+// bookmarked and listed on the page it is written on.
+func TestHeadingAtPageBottom(t *testing.T) {
+	var pdf SimPDF
+	if err := pdf.Start("Letter", false, defaults.BasicStyle, defaults.Narrow_Margins, ""); err != nil {
+		t.Fatal(err)
+	}
+	style, err := pdf.StyleName("Heading 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.PDF.SetY(pdf.pageBottom() - style.LineSize/2)
+	pdf.Write("Heading 1", models.Alignments{Left: true}, "Bottom heading")
+	if got := pdf.PDF.PageNo(); got != 2 {
+		t.Fatalf("heading written on page %d, want 2", got)
+	}
+	if len(pdf.headings) != 1 || pdf.headings[0].page != 2 {
+		t.Errorf("headings = %+v, want one on page 2", pdf.headings)
+	}
+	if _, err := pdf.Bytes(); err != nil {
+		t.Errorf("Bytes() = %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package simpdf
+
+import "errors"
+
+var (
+	// ErrStyleNotFound is returned when a style name is not part of SimPDF.Style.
+	ErrStyleNotFound = errors.New("simpdf: style not found")
+	// ErrFontNotFound is returned when a font file or font directory cannot be accessed.
+	ErrFontNotFound = errors.New("simpdf: font not found")
+	// ErrImageNotFound is returned when an image file cannot be accessed.
+	ErrImageNotFound = errors.New("simpdf: image not found")
+	// ErrAnchorNotFound is returned when a link points to a SimPDF.Anchor() that was never set.
+	ErrAnchorNotFound = errors.New("simpdf: anchor not found")
+	// ErrTableOfContentsFull is returned when the headings do not fit on the pages reserved by
+	// SimPDF.ReserveTableOfContents().
+	ErrTableOfContentsFull = errors.New("simpdf: table of contents does not fit its reserved pages")
+	// ErrInvalidPosition is returned when a Standard Position is not one of "tl", "tc", "tr",
+	// "cl", "cc", "cr", "bl", "bc", or "br".
+	ErrInvalidPosition = errors.New("simpdf: invalid standard position")
+	// ErrInvalidColumnWidth is returned when a column width of ParseColumnWidths() is not one of
+	// "auto", points, a percent, or a fraction.
+	ErrInvalidColumnWidth = errors.New("simpdf: invalid column width")
+	// ErrInvalidTableSource is returned when TableFromStructs() is not given a slice of structs.
+	ErrInvalidTableSource = errors.New("simpdf: invalid table source")
+)
+
+// Err returns true if an error has occurred while building the PDF document. Once an
+// error has occurred the remaining calls that add to the document do nothing, and the
+// error is returned by SimPDF.Finish(), SimPDF.FinishTo(), and SimPDF.Bytes().
+// This works the same way as gofpdf.Fpdf.Err().
+func (s *SimPDF) Err() bool {
+	return s.Error() != nil
+}
+
+// Error returns the first error that occurred while building the PDF document, or nil.
+// Use errors.Is() to check it against ErrStyleNotFound, ErrImageNotFound, and the like.
+func (s *SimPDF) Error() error {
+	if s.PDF == nil {
+		return s.err
+	}
+	return s.PDF.Error()
+}
+
+// SetError records err as the error of the PDF document if none has occurred yet.
+// A nil err is ignored.
+func (s *SimPDF) SetError(err error) {
+	if err == nil || s.Err() {
+		return
+	}
+	if s.PDF == nil {
+		s.err = err
+		return
+	}
+	s.PDF.SetError(err)
+}
+
+// ClearError clears the error of the PDF document so building can continue. Only use this
+// when the cause of the error is known to leave the document in a valid state.
+func (s *SimPDF) ClearError() {
+	s.err = nil
+	if s.PDF != nil {
+		s.PDF.ClearError()
+	}
+}
@@ -0,0 +1,27 @@
+package simpdf
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/braddschick/simpdf/pkg/defaults"
+	"github.com/braddschick/simpdf/pkg/models"
+)
+
+func TestWriteUnknownStyle(t *testing.T) {
+	var pdf SimPDF
+	if err := pdf.Start("Letter", false, defaults.BasicStyle, defaults.Narrow_Margins, ""); err != nil {
+		t.Fatal(err)
+	}
+	pdf.Write("", models.Alignments{}, "An empty style name is Normal.")
+	if err := pdf.Error(); err != nil {
+		t.Fatalf("Write() with an empty style name: %v", err)
+	}
+	pdf.Write("Nope", models.Alignments{}, "text")
+	if err := pdf.Error(); !errors.Is(err, ErrStyleNotFound) {
+		t.Errorf("Error() = %v, want ErrStyleNotFound", err)
+	}
+	if _, err := pdf.Bytes(); !errors.Is(err, ErrStyleNotFound) {
+		t.Errorf("Bytes() error = %v, want ErrStyleNotFound", err)
+	}
+}
@@ -1,25 +1,273 @@
 package simpdf
 
-import "github.com/braddschick/simpdf/pkg/models"
+import (
+	"math"
 
+	"github.com/braddschick/simpdf/pkg/models"
+)
+
+// HeaderFooters struct holds the contents of a header or a footer of the PDF document.
+// Each SimPDF has its own header and footer, set with SimPDF.SetHeader() and SimPDF.SetFooter().
 type HeaderFooters struct {
 	LeftContent   models.Contents
 	RightContent  models.Contents
 	CenterContent models.Contents
+	// LeftImage, CenterImage, and RightImage are written at the top of their position, example
+	// a company logo, with any text of the position written below the image. An image with only
+	// a Width or a Height is kept in proportion, and one with neither is its natural size.
+	LeftImage   Images
+	CenterImage Images
+	RightImage  Images
+	// Height is the fixed height of the band the header or footer is written in. The band
+	// grows to fit its contents if they are taller. Page contents start below a header band
+	// and end above a footer band.
+	Height float64
+	// FirstPage replaces this header or footer on the first page. Use &HeaderFooters{} to
+	// leave the first page, example a cover page, without a header or footer.
+	FirstPage *HeaderFooters
+	// OddPage replaces this header or footer on odd pages other than the first page.
+	OddPage *HeaderFooters
+	// EvenPage replaces this header or footer on even pages.
+	EvenPage *HeaderFooters
+	// Mirror swaps the left and right contents on even pages when there is no EvenPage, and
+	// moves page numbers to the outside edge: right on odd pages and left on even pages.
+	Mirror bool
+	// footer is set by SimPDF.SetFooter() so separators are drawn above rather than below.
+	footer bool
+}
+
+// NewHeaderFooters returns an empty HeaderFooters with each content aligned to its side
+// of the page.
+func NewHeaderFooters() HeaderFooters {
+	return HeaderFooters{
+		LeftContent:   models.Contents{Align: models.Alignments{Left: true}},
+		RightContent:  models.Contents{Align: models.Alignments{Right: true}},
+		CenterContent: models.Contents{Align: models.Alignments{Center: true}},
+	}
 }
 
+// Write writes the header or footer contents to the current page of s, using the FirstPage,
+// OddPage, or EvenPage variant when the page calls for one. Contents without an alignment are
+// aligned to their side of the page, and variables such as "{title}" within the contents Text
+// are replaced as described by SimPDF.SetVariable(). The page number Separator, if any, is
+// drawn below a header or above a footer.
 func (hf *HeaderFooters) Write(s *SimPDF) {
-	html := s.PDF.HTMLBasicNew()
-	if !hf.LeftContent.Empty() {
-		s.SetStyle(hf.LeftContent.Style, false)
-		html.Write(hf.LeftContent.Style.LineSize, hf.LeftContent.ToHTML(s.PDF.PageNo()))
-	}
-	if !hf.CenterContent.Empty() {
-		s.SetStyle(hf.CenterContent.Style, false)
-		html.Write(hf.CenterContent.Style.LineSize, hf.CenterContent.ToHTML(s.PDF.PageNo()))
-	}
-	if !hf.RightContent.Empty() {
-		s.SetStyle(hf.RightContent.Style, false)
-		html.Write(hf.RightContent.Style.LineSize, hf.RightContent.ToHTML(s.PDF.PageNo()))
+	page := hf.forPage(s.PDF.PageNo())
+	page.write(s)
+}
+
+// forPage returns the variant of the header or footer for page pg, mirrored if need be.
+func (hf *HeaderFooters) forPage(pg int) HeaderFooters {
+	out := *hf
+	even := pg%2 == 0
+	switch {
+	case pg == 1 && hf.FirstPage != nil:
+		out = *hf.FirstPage
+	case even && hf.EvenPage != nil:
+		out = *hf.EvenPage
+	case !even && pg != 1 && hf.OddPage != nil:
+		out = *hf.OddPage
+	}
+	out.footer = hf.footer
+	if !hf.Mirror {
+		return out
+	}
+	if even && hf.EvenPage == nil {
+		out.swapSides()
+	}
+	// The outside edge is the right on odd pages and the left on even pages.
+	inside, outside := out.LeftContent, out.RightContent
+	if even {
+		inside, outside = outside, inside
+	}
+	if inside.PageNumber && !outside.PageNumber {
+		out.swapSides()
+	}
+	return out
+}
+
+// swapSides swaps the left and right contents along with their left and right alignments.
+func (hf *HeaderFooters) swapSides() {
+	hf.LeftContent, hf.RightContent = hf.RightContent, hf.LeftContent
+	for _, c := range []*models.Contents{&hf.LeftContent, &hf.RightContent} {
+		c.Align.Left, c.Align.Right = c.Align.Right, c.Align.Left
+	}
+}
+
+// write writes the contents of this variant of the header or footer to the current page.
+// A header band starts at the top margin and the page contents continue below it. A footer
+// band ends half the bottom margin above the bottom of the page.
+func (hf *HeaderFooters) write(s *SimPDF) {
+	sep, width := hf.separator()
+	band := hf.bandHeight()
+	var top float64
+	if hf.footer {
+		top = s.Height() - (s.Margin.Bottom / 2) - band
+		if width > 0 {
+			top -= width + 2
+			s.drawRule(top, sep.Color, width)
+			top += width + 2
+		}
+	} else {
+		top = s.PDF.GetY()
+	}
+	images := hf.images()
+	for i, c := range hf.contents() {
+		if c.Align.Empty() {
+			c.Align = slotAlignments[i]
+		}
+		y := top
+		if images[i].FilePath != "" {
+			s.AddImageXY(images[i], hf.imageX(s, images[i], c.Align), y)
+			y += images[i].Height
+		}
+		if c.Empty() {
+			continue
+		}
+		c.Text = s.expandVariables(c.Text, c.Numbering)
+		s.SetStyle(c.Style, false)
+		align := c.Align.ToPDF()
+		if align == "J" {
+			align = "L"
+		}
+		s.PDF.SetXY(s.Margin.Left, y)
+		s.PDF.CellFormat(s.Width()-s.Margin.Left-s.Margin.Right, c.Style.LineSize, c.AddPage(s.PDF.PageNo()), "", 0, align, false, 0, "")
+	}
+	if !hf.footer {
+		y := top + band
+		if width > 0 {
+			y += 2
+			s.drawRule(y, sep.Color, width)
+			y += width + 2
+		}
+		s.PDF.SetXY(s.Margin.Left, y)
+	}
+}
+
+// imageX returns the X coordinate of image when aligned within the band by align.
+func (hf *HeaderFooters) imageX(s *SimPDF, image Images, align models.Alignments) float64 {
+	switch align.ToPDF() {
+	case "C":
+		return (s.Width() - image.Width) / 2
+	case "R":
+		return s.Width() - s.Margin.Right - image.Width
+	}
+	return s.Margin.Left
+}
+
+// sizeImages returns the header or footer, and its variants, with the missing Width or Height
+// of each image worked out so the band is measured and written with the size drawn.
+func (s *SimPDF) sizeImages(hf HeaderFooters) HeaderFooters {
+	for _, img := range []*Images{&hf.LeftImage, &hf.CenterImage, &hf.RightImage} {
+		if img.FilePath != "" {
+			*img = s.imageSize(*img)
+		}
+	}
+	for _, v := range []**HeaderFooters{&hf.FirstPage, &hf.OddPage, &hf.EvenPage} {
+		if *v != nil {
+			sized := s.sizeImages(**v)
+			*v = &sized
+		}
+	}
+	return hf
+}
+
+// slotAlignments are the alignments of the contents returned by HeaderFooters.contents()
+// when they have none of their own.
+var slotAlignments = []models.Alignments{{Left: true}, {Center: true}, {Right: true}}
+
+// contents returns the left, center, and right contents in that order.
+func (hf *HeaderFooters) contents() []models.Contents {
+	return []models.Contents{hf.LeftContent, hf.CenterContent, hf.RightContent}
+}
+
+// images returns the left, center, and right images in that order.
+func (hf *HeaderFooters) images() []Images {
+	return []Images{hf.LeftImage, hf.CenterImage, hf.RightImage}
+}
+
+// contentHeight returns the height of the tallest position, its image plus its text.
+func (hf *HeaderFooters) contentHeight() float64 {
+	var height float64
+	images := hf.images()
+	for i, c := range hf.contents() {
+		h := images[i].Height
+		if !c.Empty() {
+			h += c.Style.LineSize
+		}
+		height = math.Max(height, h)
+	}
+	return height
+}
+
+// bandHeight returns the height of the band the contents are written in, at least Height.
+// The separator is not included.
+func (hf *HeaderFooters) bandHeight() float64 {
+	return math.Max(hf.Height, hf.contentHeight())
+}
+
+// reserved returns the height taken from the page by this header or footer, and by all its
+// variants, including the separator.
+func (hf *HeaderFooters) reserved() float64 {
+	height := hf.bandHeight()
+	if _, width := hf.separator(); width > 0 {
+		height += width + 4
+	}
+	for _, v := range []*HeaderFooters{hf.FirstPage, hf.OddPage, hf.EvenPage} {
+		if v != nil {
+			vc := *v
+			vc.footer = hf.footer
+			height = math.Max(height, vc.reserved())
+		}
+	}
+	return height
+}
+
+// separator returns the line drawn between the header or footer and the page contents,
+// and its width. The page number Separator is used first, then the Style.Border of the
+// contents: Width.Bottom below a header and Width.Top above a footer.
+func (hf *HeaderFooters) separator() (models.Borders, float64) {
+	var borders []models.Borders
+	for _, c := range hf.contents() {
+		if c.PageNumber {
+			borders = append(borders, c.Numbering.Separator)
+		}
+	}
+	for _, c := range hf.contents() {
+		borders = append(borders, c.Style.Border)
+	}
+	for _, b := range borders {
+		if hf.footer && b.Width.Top > 0 {
+			return b, b.Width.Top
+		}
+		if !hf.footer && b.Width.Bottom > 0 {
+			return b, b.Width.Bottom
+		}
+	}
+	return models.Borders{}, 0
+}
+
+// allContents returns the contents of this header or footer and of all its variants.
+func (hf *HeaderFooters) allContents() []models.Contents {
+	out := hf.contents()
+	for _, v := range []*HeaderFooters{hf.FirstPage, hf.OddPage, hf.EvenPage} {
+		if v != nil {
+			out = append(out, v.allContents()...)
+		}
+	}
+	return out
+}
+
+// registerPageAliases registers the replacement of every "{pages}" alias the header and
+// footer may write, now that the total number of pages is known. The plain "{nb}" alias
+// is replaced by gofpdf.Fpdf.AliasNbPages() itself.
+func (s *SimPDF) registerPageAliases() {
+	pages := s.PDF.PageCount()
+	for _, hf := range []HeaderFooters{s.Header, s.Footer} {
+		for _, c := range hf.allContents() {
+			if alias := c.Numbering.TotalAlias(); alias != "{nb}" {
+				s.PDF.RegisterAlias(alias, c.Numbering.Total(pages))
+			}
+		}
 	}
 }
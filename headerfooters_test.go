@@ -0,0 +1,93 @@
+package simpdf
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/braddschick/simpdf/pkg/defaults"
+	"github.com/braddschick/simpdf/pkg/models"
+)
+
+// TestHeaderFootersParallel builds documents in parallel goroutines, each with its own header,
+// footer, and variables, and checks each is written with only its own. Run it with -race.
+func TestHeaderFootersParallel(t *testing.T) {
+	const documents = 8
+	var wg sync.WaitGroup
+	outputs := make([][]byte, documents)
+	errs := make([]error, documents)
+	for i := 0; i < documents; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var pdf SimPDF
+			if errs[i] = pdf.Start("Letter", false, defaults.BasicStyle, defaults.Narrow_Margins, ""); errs[i] != nil {
+				return
+			}
+			pdf.PDF.SetCompression(false)
+			pdf.Details(fmt.Sprintf("Document %d", i), "Author", "Subject", "Keywords")
+			pdf.SetVariable("owner", fmt.Sprintf("Owner %d", i))
+			header := NewHeaderFooters()
+			header.LeftContent.Text = "{title} header"
+			header.LeftContent.Style = defaults.Basic_Table
+			pdf.SetHeader(header)
+			footer := NewHeaderFooters()
+			footer.CenterContent.Text = "{owner} footer"
+			footer.CenterContent.Style = defaults.Basic_Table
+			pdf.SetFooter(footer)
+			for page := 0; page < 3; page++ {
+				pdf.Write("Normal", models.Alignments{Left: true}, fmt.Sprintf("Page %d of document %d", page+1, i))
+				pdf.Break()
+			}
+			outputs[i], errs[i] = pdf.Bytes()
+		}(i)
+	}
+	wg.Wait()
+	for i := 0; i < documents; i++ {
+		if errs[i] != nil {
+			t.Fatalf("document %d: %v", i, errs[i])
+		}
+		for j := 0; j < documents; j++ {
+			header := []byte(fmt.Sprintf("(Document %d header)", j))
+			footer := []byte(fmt.Sprintf("(Owner %d footer)", j))
+			want := i == j
+			if got := bytes.Contains(outputs[i], header); got != want {
+				t.Errorf("document %d has the header of document %d: %v, want %v", i, j, got, want)
+			}
+			if got := bytes.Contains(outputs[i], footer); got != want {
+				t.Errorf("document %d has the footer of document %d: %v, want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+// TestHeaderFirstPage checks that the page started by Start() gets the header however it was
+// written to before SimPDF.SetHeader(), and that writing continues below the header.
+func TestHeaderFirstPage(t *testing.T) {
+	for _, written := range []bool{false, true} {
+		var pdf SimPDF
+		if err := pdf.Start("Letter", false, defaults.BasicStyle, defaults.Narrow_Margins, ""); err != nil {
+			t.Fatal(err)
+		}
+		pdf.PDF.SetCompression(false)
+		if written {
+			pdf.PDF.SetXY(pdf.Margin.Left+10, pdf.Margin.Top)
+		}
+		header := NewHeaderFooters()
+		header.LeftContent.Text = "First page header"
+		header.LeftContent.Style = defaults.Basic_Table
+		header.Height = 40
+		pdf.SetHeader(header)
+		if y := pdf.PDF.GetY(); y < pdf.Margin.Top+header.Height {
+			t.Errorf("written %v: writing continues at %.2f, inside the header", written, y)
+		}
+		out, err := pdf.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(out, []byte("(First page header)")) {
+			t.Errorf("written %v: first page has no header", written)
+		}
+	}
+}
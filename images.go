@@ -1,12 +1,12 @@
 package simpdf
 
 import (
-	"errors"
 	"fmt"
 	"math"
 	"strings"
 
 	"github.com/braddschick/simpdf/internal"
+	"github.com/jung-kurt/gofpdf"
 )
 
 // // Additions is used for any additions to a document such as Images
@@ -34,11 +34,7 @@ func (i *Images) PointsSize() (float64, float64) {
 
 // Validate will ensure the image is accessible in the file system
 func (i *Images) Validate() bool {
-	if internal.ValidateFilePath(i.FilePath) {
-		return true
-	}
-	internal.IfError(fmt.Sprintf("Failed to find image file path (%s) was NOT found", i.FilePath), nil, false)
-	return false
+	return internal.ValidateFilePath(i.FilePath)
 }
 
 // ChangeWidth will change the images width and also modify the corresponding height to ensure proportions are correct.
@@ -63,13 +59,46 @@ func NewImage(filePath string, width, height float64) (Images, error) {
 	i.Height = height
 	i.Extension = internal.FileExtension(filePath)
 	if !i.Validate() {
-		return Images{}, errors.New("failed to find the image or was not allowed to access image file")
+		return Images{}, fmt.Errorf("%w: %s cannot be accessed or does not exist", ErrImageNotFound, filePath)
 	}
 	return i, nil
 }
 
+// imageSize returns the image with a missing Width or Height worked out from the other and the
+// proportions of the image file, or its natural size when it has neither. An image file that
+// cannot be accessed is recorded as an error wrapping ErrImageNotFound.
+func (s *SimPDF) imageSize(img Images) Images {
+	if !img.Validate() {
+		s.SetError(fmt.Errorf("%w: %s cannot be accessed or does not exist", ErrImageNotFound, img.FilePath))
+		return Images{}
+	}
+	if img.Width == 0 || img.Height == 0 {
+		info := s.PDF.RegisterImageOptions(img.FilePath, gofpdf.ImageOptions{ReadDpi: true})
+		if s.Err() {
+			return Images{}
+		}
+		switch {
+		case img.Width == 0 && img.Height == 0:
+			img.Width, img.Height = info.Width(), info.Height()
+		case img.Width == 0:
+			img.Width = math.Round(img.Height * info.Width() / info.Height())
+		default:
+			img.Height = math.Round(img.Width * info.Height() / info.Width())
+		}
+	}
+	return img
+}
+
 // AddImageXY Simply allows the adding of an image to the specifc X Y coordinates
+// An image file that cannot be accessed is recorded as an error wrapping ErrImageNotFound.
 func (s *SimPDF) AddImageXY(image Images, x, y float64) {
+	if s.Err() {
+		return
+	}
+	if !image.Validate() {
+		s.SetError(fmt.Errorf("%w: %s cannot be accessed or does not exist", ErrImageNotFound, image.FilePath))
+		return
+	}
 	// if image.Extension == "PNG" {
 	// 	s.PDF.ImageOptions(image.FilePath, x, y, image.Width, image.Height, false, gofpdf.ImageOptions{ImageType: image.Extension, ReadDpi: true}, 0, "")
 	// } else {
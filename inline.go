@@ -0,0 +1,552 @@
+package simpdf
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/braddschick/simpdf/pkg/models"
+)
+
+// markKinds are the kinds of inline markup understood by SimPDF.Parser().
+type markKinds int
+
+const (
+	markText markKinds = iota
+	markBold
+	markItalic
+	markUnderline
+	markStrike
+	markSuperscript
+	markSubscript
+	markLink
+	markStyle
+)
+
+// markRoles tells whether a marker opens, closes, or toggles its kind of markup.
+type markRoles int
+
+const (
+	roleText markRoles = iota
+	roleOpen
+	roleClose
+	roleToggle
+)
+
+// inlineMarkers are the markers of the built-in inline markup by name, longest first where
+// they share a prefix. The closing "](target)" of a link is matched separately.
+var inlineMarkers = []struct {
+	name string
+	raw  string
+	kind markKinds
+	role markRoles
+}{
+	{"bold", "__", markBold, roleToggle},
+	{"italic", "_*", markItalic, roleOpen},
+	{"italic", "*_", markItalic, roleClose},
+	{"underline", "_#", markUnderline, roleOpen},
+	{"underline", "#_", markUnderline, roleClose},
+	{"strike", "~~", markStrike, roleToggle},
+	{"superscript", "^", markSuperscript, roleOpen},
+	{"subscript", "~", markSubscript, roleOpen},
+	{"link", "[", markLink, roleOpen},
+}
+
+// styleMark starts and ends the control markers written by simpdf.StyleGroup around the text
+// to be written in a style. styleEnd ends the last style started.
+const (
+	styleMark = "\x02"
+	styleEnd  = "\x02\x03"
+)
+
+// styleStart returns the control marker starting the style of the name given.
+func styleStart(name string) string {
+	return styleMark + name + "\x03"
+}
+
+// inlineEscapes are the characters a backslash writes as is.
+const inlineEscapes = `\_*#~^[]()`
+
+// inlineToken is either literal text or a marker of the inline markup.
+type inlineToken struct {
+	kind markKinds
+	role markRoles
+	// text is the literal text, or the marker as written should it be left unmatched.
+	text string
+	// target is the link target of a link marker or the style name of a style marker.
+	target string
+	// matched is true for a marker that has been paired with its opening or closing marker.
+	matched bool
+}
+
+// span is a run of text written in one variation of the style.
+type span struct {
+	text        string
+	bold        bool
+	italic      bool
+	underline   bool
+	strike      bool
+	superscript bool
+	subscript   bool
+	link        string
+	style       string
+}
+
+// formatted returns true if the span is written differently to plain text.
+func (sp span) formatted() bool {
+	return sp.bold || sp.italic || sp.underline || sp.strike || sp.superscript || sp.subscript ||
+		sp.link != "" || sp.style != ""
+}
+
+// sameFormat returns true if both spans are written the same way.
+func (sp span) sameFormat(other span) bool {
+	other.text = sp.text
+	return sp == other
+}
+
+// formatted returns true if any of the spans are written differently to plain text.
+func formatted(spans []span) bool {
+	for _, sp := range spans {
+		if sp.formatted() {
+			return true
+		}
+	}
+	return false
+}
+
+// joinSpans returns the text of all the spans.
+func joinSpans(spans []span) string {
+	var b strings.Builder
+	for _, sp := range spans {
+		b.WriteString(sp.text)
+	}
+	return b.String()
+}
+
+// scriptEnd returns the index of the marker closing the superscript or subscript marker
+// that rest starts with, or -1 if there is none. As in pandoc the text between the markers
+// may not be empty or hold spaces, so "2^10 and 3^2" is written as is.
+func scriptEnd(rest string) int {
+	for i := 1; i < len(rest); i++ {
+		switch c := rest[i]; {
+		case c == '\\' && i+1 < len(rest) && strings.IndexByte(inlineEscapes, rest[i+1]) >= 0:
+			i++
+		case c == rest[0] && i > 1:
+			return i
+		case c == rest[0] || c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			return -1
+		}
+	}
+	return -1
+}
+
+// lexInline splits text into literal text and markers, leaving the markers of the disabled
+// built-in markup as literal text.
+func lexInline(text string, disabled map[string]bool) []inlineToken {
+	var tokens []inlineToken
+	var lit strings.Builder
+	flush := func() {
+		if lit.Len() > 0 {
+			tokens = append(tokens, inlineToken{kind: markText, role: roleText, text: lit.String()})
+			lit.Reset()
+		}
+	}
+	// closes holds the kind of the superscript and subscript markers closed at each index.
+	closes := make(map[int]markKinds)
+next:
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		if kind, ok := closes[i]; ok {
+			flush()
+			tokens = append(tokens, inlineToken{kind: kind, role: roleClose, text: rest[:1]})
+			delete(closes, i)
+			i++
+			continue
+		}
+		if rest[0] == '\\' && len(rest) > 1 && strings.IndexByte(inlineEscapes, rest[1]) >= 0 {
+			lit.WriteByte(rest[1])
+			i += 2
+			continue
+		}
+		if strings.HasPrefix(rest, styleMark) {
+			if end := strings.IndexByte(rest, '\x03'); end > 0 {
+				flush()
+				t := inlineToken{kind: markStyle, role: roleOpen, target: rest[1:end]}
+				if t.target == "" {
+					t.role = roleClose
+				}
+				tokens = append(tokens, t)
+				i += end + 1
+				continue
+			}
+		}
+		if strings.HasPrefix(rest, "[[") && !disabled["style"] {
+			if end := strings.Index(rest, "]]"); end > 2 && !strings.ContainsAny(rest[2:end], "[]\n") {
+				flush()
+				t := inlineToken{kind: markStyle, role: roleOpen, text: rest[:end+2], target: rest[2:end]}
+				if t.target == "/" {
+					t.role = roleClose
+				}
+				tokens = append(tokens, t)
+				i += end + 2
+				continue
+			}
+		}
+		if strings.HasPrefix(rest, "](") && !disabled["link"] {
+			if end := strings.IndexByte(rest, ')'); end > 2 && !strings.ContainsAny(rest[2:end], " \t\n") {
+				flush()
+				tokens = append(tokens, inlineToken{kind: markLink, role: roleClose, text: rest[:end+1], target: rest[2:end]})
+				i += end + 1
+				continue
+			}
+		}
+		for _, m := range inlineMarkers {
+			if strings.HasPrefix(rest, m.raw) && !disabled[m.name] {
+				if m.kind == markSuperscript || m.kind == markSubscript {
+					end := scriptEnd(rest)
+					if end < 0 {
+						break
+					}
+					closes[i+end] = m.kind
+				}
+				flush()
+				tokens = append(tokens, inlineToken{kind: m.kind, role: m.role, text: m.raw})
+				i += len(m.raw)
+				continue next
+			}
+		}
+		_, size := utf8.DecodeRuneInString(rest)
+		lit.WriteString(rest[:size])
+		i += size
+	}
+	flush()
+	return tokens
+}
+
+// pairInline matches each closing marker with the nearest open marker of its kind. Markers
+// left unmatched are written as literal text.
+func pairInline(tokens []inlineToken) {
+	var open []int
+	for i := range tokens {
+		t := &tokens[i]
+		if t.role == roleText {
+			continue
+		}
+		if t.role == roleOpen {
+			open = append(open, i)
+			continue
+		}
+		j := len(open) - 1
+		for ; j >= 0; j-- {
+			if tokens[open[j]].kind == t.kind {
+				break
+			}
+		}
+		if j < 0 {
+			if t.role == roleToggle {
+				open = append(open, i)
+			}
+			continue
+		}
+		o := &tokens[open[j]]
+		o.role, o.matched = roleOpen, true
+		t.role, t.matched = roleClose, true
+		if t.kind == markLink {
+			o.target = t.target
+		}
+		open = append(open[:j], open[j+1:]...)
+	}
+}
+
+// buildSpans returns the text of the tokens as spans, merging neighbours written the same way.
+func buildSpans(tokens []inlineToken) []span {
+	var spans []span
+	var depth [markStyle + 1]int
+	var links, styles []string
+	for _, t := range tokens {
+		if t.role != roleText && t.matched {
+			switch {
+			case t.kind == markLink && t.role == roleOpen:
+				links = append(links, t.target)
+			case t.kind == markLink:
+				links = links[:len(links)-1]
+			case t.kind == markStyle && t.role == roleOpen:
+				styles = append(styles, t.target)
+			case t.kind == markStyle:
+				styles = styles[:len(styles)-1]
+			case t.role == roleOpen:
+				depth[t.kind]++
+			default:
+				depth[t.kind]--
+			}
+			continue
+		}
+		sp := span{
+			text:        t.text,
+			bold:        depth[markBold] > 0,
+			italic:      depth[markItalic] > 0,
+			underline:   depth[markUnderline] > 0,
+			strike:      depth[markStrike] > 0,
+			superscript: depth[markSuperscript] > 0,
+			subscript:   depth[markSubscript] > 0,
+		}
+		if len(links) > 0 {
+			sp.link = links[len(links)-1]
+		}
+		if len(styles) > 0 {
+			sp.style = styles[len(styles)-1]
+		}
+		if n := len(spans); n > 0 && spans[n-1].sameFormat(sp) {
+			spans[n-1].text += sp.text
+			continue
+		}
+		spans = append(spans, sp)
+	}
+	return spans
+}
+
+// scriptScale is the size of superscript and subscript text relative to the style.
+const scriptScale = 0.65
+
+// spanFont sets the font, and text color, of the span written in the style, or in the style
+// the span names. The style used is returned.
+func (s *SimPDF) spanFont(style models.Styles, sp span) models.Styles {
+	if sp.style != "" {
+		named, err := s.StyleName(sp.style)
+		if err != nil {
+			named, err = s.StyleName("Normal")
+		}
+		if err == nil {
+			style = named
+		}
+	}
+	variant := ""
+	if style.TextVariant.Bold || sp.bold {
+		variant += "B"
+	}
+	if style.TextVariant.Italic || sp.italic {
+		variant += "I"
+	}
+	if style.TextVariant.Underline || sp.underline || sp.link != "" {
+		variant += "U"
+	}
+	if style.TextVariant.StrikeOut || sp.strike {
+		variant += "S"
+	}
+	size := style.TextSize
+	if sp.superscript || sp.subscript {
+		size *= scriptScale
+	}
+	s.PDF.SetFont(style.Font.Name, variant, size)
+	if sp.link != "" {
+		s.PDF.SetTextColor(0, 0, 128)
+	} else {
+		s.PDF.SetTextColor(int(style.Color.Red), int(style.Color.Green), int(style.Color.Blue))
+	}
+	return style
+}
+
+// highlighted returns true if the span names a style with a BackgroundColor, written behind
+// the span. A BackgroundColor left as is, black, is not written.
+func highlighted(style models.Styles, sp span) bool {
+	return sp.style != "" && style.BackgroundColor != (models.RGBColor{})
+}
+
+// spanOffset returns how far the span is moved down from the line, negative being up.
+func spanOffset(style models.Styles, sp span) float64 {
+	if sp.superscript {
+		return -style.TextSize * 0.3
+	}
+	if sp.subscript {
+		return style.TextSize * 0.2
+	}
+	return 0
+}
+
+// inlinePiece is a word, or the spaces between words, of a span along with its width.
+type inlinePiece struct {
+	text    string
+	span    span
+	width   float64
+	space   bool
+	newline bool
+}
+
+// inlineLine is the pieces written on one line.
+type inlineLine struct {
+	pieces []inlinePiece
+	// broken is true for a line ended by a line break in the text, never justified.
+	broken bool
+}
+
+// measure returns the width of the line and the number of its spaces.
+func (l inlineLine) measure() (float64, int) {
+	width, spaces := 0.0, 0
+	for _, p := range l.pieces {
+		width += p.width
+		if p.space {
+			spaces++
+		}
+	}
+	return width, spaces
+}
+
+// joinPieces returns the pieces with neighbours written the same way joined together.
+func joinPieces(pieces []inlinePiece) []inlinePiece {
+	var out []inlinePiece
+	for _, p := range pieces {
+		if n := len(out); n > 0 && out[n-1].span.sameFormat(p.span) {
+			out[n-1].text += p.text
+			out[n-1].width += p.width
+			out[n-1].space = false
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// inlinePieces splits the spans into words, spaces, and line breaks measured in the style.
+func (s *SimPDF) inlinePieces(style models.Styles, spans []span) []inlinePiece {
+	var pieces []inlinePiece
+	for _, sp := range spans {
+		s.spanFont(style, sp)
+		for i, line := range strings.Split(sp.text, "\n") {
+			if i > 0 {
+				pieces = append(pieces, inlinePiece{span: sp, newline: true})
+			}
+			for len(line) > 0 {
+				space := line[0] == ' '
+				end := strings.IndexFunc(line, func(r rune) bool { return (r == ' ') != space })
+				if end < 0 {
+					end = len(line)
+				}
+				pieces = append(pieces, inlinePiece{text: line[:end], span: sp, width: s.StringWidth(line[:end]), space: space})
+				line = line[end:]
+			}
+		}
+	}
+	return pieces
+}
+
+// wrapPieces lays the pieces out in lines no wider than width, the first line being no
+// wider than first. Words wider than a line are split between lines.
+func (s *SimPDF) wrapPieces(style models.Styles, pieces []inlinePiece, first, width float64) []inlineLine {
+	var lines []inlineLine
+	var line []inlinePiece
+	used, avail := 0.0, first
+	flush := func(broken bool) {
+		for len(line) > 0 && line[len(line)-1].space {
+			line = line[:len(line)-1]
+		}
+		lines = append(lines, inlineLine{pieces: line, broken: broken})
+		line, used, avail = nil, 0, width
+	}
+	for _, p := range pieces {
+		switch {
+		case p.newline:
+			flush(true)
+			continue
+		case p.space:
+			if len(line) > 0 || len(lines) == 0 {
+				line = append(line, p)
+				used += p.width
+			}
+			continue
+		}
+		if used+p.width > avail && (len(line) > 0 || avail < width) {
+			flush(false)
+		}
+		for p.width > avail && utf8.RuneCountInString(p.text) > 1 {
+			s.spanFont(style, p.span)
+			head := []rune(p.text)
+			for len(head) > 1 && s.StringWidth(string(head)) > avail {
+				head = head[:len(head)-1]
+			}
+			line = append(line, inlinePiece{text: string(head), span: p.span, width: s.StringWidth(string(head))})
+			flush(false)
+			p.text = p.text[len(string(head)):]
+			p.width = s.StringWidth(p.text)
+		}
+		line = append(line, p)
+		used += p.width
+	}
+	flush(false)
+	return lines
+}
+
+// writeSpans writes the spans in the style from the current position, wrapping them
+// between the margins. align is "L" left, "C" center, "R" right, or "J" justified; centered
+// and right aligned text starts on a new line. The position is left after the last span as
+// gofpdf.Fpdf.Write() does.
+func (s *SimPDF) writeSpans(style models.Styles, align string, spans []span) {
+	lineHt := style.LineSize
+	left, _, right, _ := s.PDF.GetMargins()
+	pageW, pageH := s.PDF.GetPageSize()
+	width := pageW - left - right
+	cm := s.PDF.GetCellMargin()
+	fillR, fillG, fillB := s.PDF.GetFillColor()
+	s.PDF.SetCellMargin(0)
+	defer func() {
+		s.PDF.SetCellMargin(cm)
+		s.PDF.SetFillColor(fillR, fillG, fillB)
+	}()
+
+	x := s.PDF.GetX()
+	if align != "L" && align != "J" && x > left {
+		s.PDF.Ln(lineHt)
+		x = left
+	}
+	lines := s.wrapPieces(style, s.inlinePieces(style, spans), width-(x-left), width)
+	auto, breakMargin := s.PDF.GetAutoPageBreak()
+	for i, line := range lines {
+		if i > 0 {
+			s.PDF.Ln(lineHt)
+			x = left
+		}
+		if auto && s.PDF.GetY()+lineHt > pageH-breakMargin {
+			s.PDF.AddPage()
+			x = left
+		}
+		avail := width - (x - left)
+		lineW, spaces := line.measure()
+		gap := 0.0
+		switch align {
+		case "C":
+			x += (avail - lineW) / 2
+		case "R":
+			x += avail - lineW
+		case "J":
+			if i < len(lines)-1 && spaces > 0 && !line.broken {
+				gap = (avail - lineW) / float64(spaces)
+			}
+		}
+		pieces := line.pieces
+		if gap == 0 {
+			pieces = joinPieces(pieces)
+		}
+		y := s.PDF.GetY()
+		var used models.Styles
+		for j, p := range pieces {
+			w := p.width
+			if p.space {
+				w += gap
+			}
+			if j == 0 || !p.span.sameFormat(pieces[j-1].span) {
+				used = s.spanFont(style, p.span)
+			}
+			if highlighted(used, p.span) {
+				bg := used.BackgroundColor
+				s.PDF.SetFillColor(int(bg.Red), int(bg.Green), int(bg.Blue))
+				s.PDF.Rect(x, y, w, lineHt, "F")
+			}
+			link, linkStr := 0, ""
+			if p.span.link != "" {
+				link, linkStr = s.linkTarget(p.span.link)
+			}
+			s.PDF.SetXY(x, y+spanOffset(style, p.span))
+			s.PDF.CellFormat(w, lineHt, p.text, "", 0, "L", false, link, linkStr)
+			x += w
+		}
+		s.PDF.SetXY(x, y)
+	}
+}
@@ -0,0 +1,71 @@
+package simpdf
+
+import (
+	"reflect"
+	"testing"
+)
+
+// parse returns the spans of text with all of the built-in markup enabled.
+func parse(text string) []span {
+	tokens := lexInline(text, nil)
+	pairInline(tokens)
+	return buildSpans(tokens)
+}
+
+func TestInlineSpans(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []span
+	}{
+		{"plain", "plain text", []span{{text: "plain text"}}},
+		{"bold", "a __bold__ word", []span{{text: "a "}, {text: "bold", bold: true}, {text: " word"}}},
+		{"superscript", "x^2^ + y^2^", []span{
+			{text: "x"}, {text: "2", superscript: true}, {text: " + y"}, {text: "2", superscript: true},
+		}},
+		{"subscript", "H~2~O", []span{{text: "H"}, {text: "2", subscript: true}, {text: "O"}}},
+		{"tildes across text", "approx ~5 to ~10", []span{{text: "approx ~5 to ~10"}}},
+		{"carets across text", "2^10 and 3^2", []span{{text: "2^10 and 3^2"}}},
+		{"empty superscript", "a ^^ b", []span{{text: "a ^^ b"}}},
+		{"strike beside subscript", "~~gone~~ H~2~O", []span{
+			{text: "gone", strike: true}, {text: " H"}, {text: "2", subscript: true}, {text: "O"},
+		}},
+		{"nested", "__bold _*and italic*___", []span{
+			{text: "bold ", bold: true}, {text: "and italic", bold: true, italic: true},
+		}},
+		{"nested in superscript", "e^__i__^", []span{{text: "e"}, {text: "i", bold: true, superscript: true}}},
+		{"escapes", `\_\_init\_\_ and 2\^10\^`, []span{{text: "__init__ and 2^10^"}}},
+		{"escape in superscript", `x^a\^b^`, []span{{text: "x"}, {text: "a^b", superscript: true}}},
+		{"unmatched", "__open and _*open", []span{{text: "__open and _*open"}}},
+		{"link", "see [docs](#intro)", []span{{text: "see "}, {text: "docs", link: "#intro"}}},
+		{"style", "[[Code]]x[[/]]", []span{{text: "x", style: "Code"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parse(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parse(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLexInlineDisabled(t *testing.T) {
+	tokens := lexInline("__a__ x^2^", map[string]bool{"bold": true, "superscript": true})
+	want := []inlineToken{{kind: markText, role: roleText, text: "__a__ x^2^"}}
+	if !reflect.DeepEqual(tokens, want) {
+		t.Errorf("lexInline() = %+v, want %+v", tokens, want)
+	}
+}
+
+func TestPairInline(t *testing.T) {
+	tokens := lexInline("__a _*b__", nil)
+	pairInline(tokens)
+	for _, tok := range tokens {
+		if tok.kind == markItalic && tok.matched {
+			t.Errorf("unclosed italic marker %q matched", tok.text)
+		}
+		if tok.kind == markBold && !tok.matched {
+			t.Errorf("bold marker %q unmatched", tok.text)
+		}
+	}
+}
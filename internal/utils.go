@@ -1,7 +1,7 @@
 package internal
 
 import (
-	"log"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -10,17 +10,14 @@ import (
 
 // ValidateFilePath ensures the given filePath is accessible and is not a directory.
 //
-// Returns true is it is accessible, false if does not exist OR path is a directory.
+// Returns true is it is accessible, false if it does not exist, cannot be accessed for any
+// other reason, OR path is a directory.
 func ValidateFilePath(filePath string) bool {
 	fileInfo, err := os.Stat(filePath)
-	if os.IsNotExist(err) {
-		return false
-	}
-	if fileInfo.IsDir() {
+	if err != nil {
 		return false
 	}
-
-	return true
+	return !fileInfo.IsDir()
 }
 
 // FileExtension returns the file extension of the given filePath in UPPERCASE for gofpdf.Pdf use.
@@ -30,15 +27,14 @@ func FileExtension(filePath string) string {
 
 // MoveFilePath safely moves file path "filePath" to the newFilePath.
 //
-// Returns false if an error has occured, true if it was a successful operation
-func MoveFilePath(filePath string, newFilePath string) bool {
-	err := os.Rename(filePath, newFilePath)
-	if err != nil {
-		log.Fatalf("MoveFilePath incurred an Error moving from %s to %s.", filePath, newFilePath)
-		return false
+// Returns an error describing both paths if the move could not be completed.
+func MoveFilePath(filePath string, newFilePath string) error {
+	if err := os.Rename(filePath, newFilePath); err != nil {
+		return fmt.Errorf("moving %s to %s: %w", filePath, newFilePath, err)
 	}
-	return true
+	return nil
 }
+
 // IsNumber safely checks the txt variable to see if it is a number
 func IsNumber(txt string) bool {
 	_, err := strconv.Atoi(txt)
@@ -47,15 +43,3 @@ func IsNumber(txt string) bool {
 	}
 	return true
 }
-
-// IfError handles an error in a common format for ease of programming.
-func IfError(msg string, err error, isPanic bool) {
-	// if err != nil {
-	// 	log.Fatalf("Error Message: %s || Actual Error: %s\n", msg, err.Error())
-	// } else {
-	// 	log.Fatal(msg)
-	// }
-	// if isPanic {
-	// 	panic(msg)
-	// }
-}
@@ -0,0 +1,52 @@
+package simpdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Anchor marks the current position of the PDF document as the target of links to "#name",
+// example the text [see the summary](#summary) written after SimPDF.Anchor("summary").
+// Links may be written before or after their anchor. A link to an anchor that is never set
+// is recorded as an error wrapping ErrAnchorNotFound when the document is finished.
+func (s *SimPDF) Anchor(name string) {
+	if s.Err() {
+		return
+	}
+	s.PDF.SetLink(s.anchorLink(name), -1, -1)
+	s.anchors[name] = true
+}
+
+// anchorLink returns the gofpdf link of the anchor name, adding it if need be.
+func (s *SimPDF) anchorLink(name string) int {
+	if s.links == nil {
+		s.links = make(map[string]int)
+		s.anchors = make(map[string]bool)
+	}
+	link, ok := s.links[name]
+	if !ok {
+		link = s.PDF.AddLink()
+		s.links[name] = link
+	}
+	return link
+}
+
+// checkAnchors records an error for the first link to an anchor that was never set.
+func (s *SimPDF) checkAnchors() {
+	for name := range s.links {
+		if !s.anchors[name] {
+			s.SetError(fmt.Errorf("%w: #%s", ErrAnchorNotFound, name))
+			return
+		}
+	}
+}
+
+// linkTarget returns the gofpdf link, or the external URL, of the link target. A target
+// starting with "#" links to the SimPDF.Anchor() of that name, any other target is an
+// external URL.
+func (s *SimPDF) linkTarget(target string) (int, string) {
+	if strings.HasPrefix(target, "#") {
+		return s.anchorLink(strings.TrimPrefix(target, "#")), ""
+	}
+	return 0, target
+}
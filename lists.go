@@ -0,0 +1,136 @@
+package simpdf
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/braddschick/simpdf/pkg/models"
+)
+
+// ListItem struct is an item of a list added by SimPDF.AddList().
+type ListItem struct {
+	// Text of the item, the inline markup of SimPDF.Parser() is observed.
+	Text string
+	// Items are nested below the item, one level deeper.
+	Items []ListItem
+	// Ordered numbers the nested Items rather than bulleting them.
+	Ordered bool
+	// Number restarts the numbering of an ordered list at this item, 0 continues it.
+	Number int
+	// Bullet replaces the bullet of the level for this item of an unordered list.
+	Bullet string
+	// BulletImage replaces the bullet with an image when its FilePath is set. An image without
+	// a Width or Height is sized to the text, and one with only either is kept in proportion.
+	BulletImage Images
+}
+
+var (
+	// DefaultListBullets are the bullets of unordered lists by level, "•", "–", and "·".
+	// Bullets are written in the encoding of the font, see SimPDF.fontText().
+	DefaultListBullets = []string{"•", "–", "·"}
+	// DefaultListNumbering is the numbering of ordered lists by level, 1., a., and i.
+	DefaultListNumbering = []models.NumberStyles{models.Arabic, models.LowerLetter, models.LowerRoman}
+)
+
+// DefaultListIndent is how far each level of a list is indented in pts.
+const DefaultListIndent = 18
+
+// coreFonts are the fonts built into PDF readers that gofpdf writes in the cp1252 encoding.
+var coreFonts = map[string]bool{"arial": true, "courier": true, "helvetica": true, "times": true}
+
+// fontText returns text in the encoding of the font of the style. UTF-8 text is translated to
+// cp1252 for a core font. Text that is not UTF-8, example "\x95" already in cp1252, and text
+// in any other font, added as a UTF-8 font, is returned as is.
+func (s *SimPDF) fontText(style models.Styles, text string) string {
+	if !coreFonts[strings.ToLower(style.Font.Name)] || !utf8.ValidString(text) {
+		return text
+	}
+	if s.cp1252 == nil {
+		s.cp1252 = s.PDF.UnicodeTranslatorFromDescriptor("")
+	}
+	return s.cp1252(text)
+}
+
+// AddList adds the items to the PDF document as a bulleted, or when ordered a numbered, list in
+// the style given. Nested items are indented a level deeper, levels past the last of
+// SimPDF.ListBullets or SimPDF.ListNumbering repeat them from the first. Text wrapping past the
+// end of the line hangs at the indent of the text, not the bullet, and an item that does not
+// fit at the bottom of the page starts a new page.
+func (s *SimPDF) AddList(style string, items []ListItem, ordered bool) {
+	if s.Err() {
+		return
+	}
+	sty, ok := s.style(style)
+	if !ok {
+		return
+	}
+	if x := s.PDF.GetX(); x > s.leftMargin() {
+		s.PDF.Ln(sty.LineSize)
+	}
+	s.writeList(sty, items, ordered, 0)
+	s.fontReset(models.Styles{})
+}
+
+// writeList writes the items of a list, and the lists nested in them, in the style at the
+// level given.
+func (s *SimPDF) writeList(style models.Styles, items []ListItem, ordered bool, level int) {
+	indent := s.ListIndent
+	if indent == 0 {
+		indent = DefaultListIndent
+	}
+	bullets := s.ListBullets
+	if len(bullets) == 0 {
+		bullets = DefaultListBullets
+	}
+	numbering := s.ListNumbering
+	if len(numbering) == 0 {
+		numbering = DefaultListNumbering
+	}
+	left := s.leftMargin()
+	textLeft := left + indent
+	bottom := s.pageBottom()
+	number := 1
+	for _, item := range items {
+		if s.Err() {
+			break
+		}
+		if item.Number != 0 {
+			number = item.Number
+		}
+		s.PDF.SetLeftMargin(textLeft)
+		if s.PDF.GetY()+style.LineSize > bottom {
+			s.Break()
+		}
+		s.SetStyle(style, false)
+		y := s.PDF.GetY()
+		switch {
+		case ordered:
+			numStyle := numbering[level%len(numbering)]
+			s.PDF.SetX(left)
+			s.PDF.CellFormat(indent, style.LineSize, numStyle.Format(number)+".", "", 0, "R", false, 0, "")
+		case item.BulletImage.FilePath != "":
+			img := item.BulletImage
+			if img.Width == 0 && img.Height == 0 {
+				img.Height = style.TextSize * 0.75
+			}
+			img = s.imageSize(img)
+			s.AddImageXY(img, left+s.PDF.GetCellMargin(), y+((style.LineSize-img.Height)/2))
+		default:
+			bullet := bullets[level%len(bullets)]
+			if item.Bullet != "" {
+				bullet = item.Bullet
+			}
+			s.PDF.SetX(left)
+			s.PDF.CellFormat(indent, style.LineSize, s.fontText(style, bullet), "", 0, "R", false, 0, "")
+		}
+		s.PDF.SetXY(textLeft, y)
+		s.writeSpans(style, "L", s.parseInline(item.Text))
+		s.PDF.Ln(style.LineSize)
+		if len(item.Items) > 0 {
+			s.writeList(style, item.Items, item.Ordered, level+1)
+		}
+		number++
+	}
+	s.PDF.SetLeftMargin(left)
+	s.PDF.SetX(left)
+}
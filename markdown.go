@@ -0,0 +1,585 @@
+package simpdf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/braddschick/simpdf/pkg/colors"
+	"github.com/braddschick/simpdf/pkg/models"
+	"github.com/jung-kurt/gofpdf"
+)
+
+var (
+	mdHeadingExp   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	mdFenceExp     = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+	mdRuleExp      = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	mdQuoteExp     = regexp.MustCompile(`^ {0,3}> ?(.*)$`)
+	mdListItemExp  = regexp.MustCompile(`^( *)([-*+]|[0-9]{1,9}[.)])(?:[ \t]+(.*))?$`)
+	mdDelimiterExp = regexp.MustCompile(`^[ \t]*\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	mdImageExp     = regexp.MustCompile(`^!\[([^\]]*)\]\(([^\s\)]+)(?:[ \t]+"[^"]*")?\)$`)
+	// mdInlineImageExp, mdLinkExp, and mdAutolinkExp match at the start of the rest of a line.
+	mdInlineImageExp = regexp.MustCompile(`^!\[([^\]]*)\]\(([^\s\)]+)(?:[ \t]+"[^"]*")?\)`)
+	mdLinkExp        = regexp.MustCompile(`^\[([^\]]+)\]\(([^\s\)]+)(?:[ \t]+"[^"]*")?\)`)
+	mdAutolinkExp    = regexp.MustCompile(`^<((?:https?|mailto):[^\s<>]+)>`)
+)
+
+// mdIndent is how far block quotes are indented.
+const mdIndent = 18
+
+// mdList is a list of items in a Markdown document.
+type mdList struct {
+	ordered bool
+	start   int
+	items   []mdListItem
+}
+
+// mdListItem is an item of a list in a Markdown document and its nested list, if any.
+type mdListItem struct {
+	text string
+	list *mdList
+}
+
+// listItems returns the items of the list, with their inline Markdown converted, for
+// SimPDF.AddList().
+func (l *mdList) listItems() []ListItem {
+	items := make([]ListItem, len(l.items))
+	for i, item := range l.items {
+		items[i].Text = markdownInline(item.text)
+		if item.list != nil {
+			items[i].Items = item.list.listItems()
+			items[i].Ordered = item.list.ordered
+		}
+	}
+	if len(items) > 0 && l.start != 1 {
+		items[0].Number = l.start
+	}
+	return items
+}
+
+// RenderMarkdown writes the Markdown document read from r to the PDF document.
+//
+// ATX headings "#" to "###" are written in the "Heading 1" to "Heading 3" styles, deeper
+// headings in "Heading 3", and so are part of the outline and table of contents. Paragraphs,
+// and ordered and unordered lists, are written in the "Normal" style, block quotes in the
+// "Quote" style, fenced code blocks and `code` in the "Code" style, and pipe tables through
+// SimPDF.AddTable() in the "Table" style. Styles that do not exist are based on "Normal".
+// A paragraph of only an image, ![alt](path/to/image.png), is written as the image at its
+// natural size, shrunk to fit the page. **bold**, *italic*, ~~strike~~, and links are written
+// as by SimPDF.Parser().
+//
+// The error of the PDF document is returned, including any error reading r.
+func (s *SimPDF) RenderMarkdown(r io.Reader) error {
+	if s.Err() {
+		return s.Error()
+	}
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, strings.Replace(scanner.Text(), "\t", "    ", -1))
+	}
+	if err := scanner.Err(); err != nil {
+		s.SetError(fmt.Errorf("simpdf: reading markdown: %w", err))
+		return s.Error()
+	}
+	body, ok := s.style("Normal")
+	if !ok {
+		return s.Error()
+	}
+	if x := s.PDF.GetX(); x > s.leftMargin() {
+		s.PDF.Ln(body.LineSize)
+	}
+	s.renderMarkdown(lines, body)
+	s.fontReset(models.Styles{})
+	return s.Error()
+}
+
+// leftMargin returns the current left margin of the PDF document.
+func (s *SimPDF) leftMargin() float64 {
+	left, _, _, _ := s.PDF.GetMargins()
+	return left
+}
+
+// markdownStyle returns the style of the name given, or else fallback renamed to name.
+// Unlike SimPDF.StyleName() no error is recorded for a style that does not exist.
+func (s *SimPDF) markdownStyle(name string, fallback models.Styles) models.Styles {
+	for _, t := range s.Style {
+		if strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+	fallback.Name = name
+	return fallback
+}
+
+// renderMarkdown writes the blocks of the Markdown lines with paragraphs in the body style.
+func (s *SimPDF) renderMarkdown(lines []string, body models.Styles) {
+	for i := 0; i < len(lines) && !s.Err(); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+		case mdFenceExp.MatchString(line):
+			fence := mdFenceExp.FindStringSubmatch(line)[1]
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			i++
+			s.markdownCode(strings.Join(code, "\n"), body)
+		case mdHeadingExp.MatchString(line):
+			m := mdHeadingExp.FindStringSubmatch(line)
+			s.markdownHeading(len(m[1]), m[2])
+			i++
+		case mdRuleExp.MatchString(line):
+			s.markdownRule(body)
+			i++
+		case mdQuoteExp.MatchString(line):
+			var quote []string
+			for ; i < len(lines) && mdQuoteExp.MatchString(lines[i]); i++ {
+				quote = append(quote, mdQuoteExp.FindStringSubmatch(lines[i])[1])
+			}
+			s.markdownQuote(quote, body)
+		case mdListItemExp.MatchString(line):
+			var list *mdList
+			list, i = parseMarkdownList(lines, i)
+			s.writeList(body, list.listItems(), list.ordered, 0)
+			s.PDF.Ln(body.LineSize / 2)
+		case strings.Contains(line, "|") && i+1 < len(lines) && mdDelimiterExp.MatchString(lines[i+1]):
+			rows := [][]string{splitMarkdownRow(line)}
+			aligns := markdownAlignments(splitMarkdownRow(lines[i+1]))
+			for i += 2; i < len(lines) && strings.Contains(lines[i], "|") && strings.TrimSpace(lines[i]) != ""; i++ {
+				rows = append(rows, splitMarkdownRow(lines[i]))
+			}
+			s.markdownTable(rows, aligns, body)
+		default:
+			var para []string
+			for ; i < len(lines) && !endsMarkdownParagraph(lines[i]); i++ {
+				para = append(para, lines[i])
+			}
+			s.markdownParagraph(para, body)
+		}
+	}
+}
+
+// endsMarkdownParagraph returns true if the line is blank or starts another block.
+func endsMarkdownParagraph(line string) bool {
+	if strings.TrimSpace(line) == "" {
+		return true
+	}
+	for _, exp := range []*regexp.Regexp{mdFenceExp, mdHeadingExp, mdRuleExp, mdQuoteExp} {
+		if exp.MatchString(line) {
+			return true
+		}
+	}
+	if m := mdListItemExp.FindStringSubmatch(line); m != nil && m[3] != "" {
+		return true
+	}
+	return false
+}
+
+// markdownHeading writes a heading of the level given.
+func (s *SimPDF) markdownHeading(level int, text string) {
+	if level > 3 {
+		level = 3
+	}
+	style := fmt.Sprintf("Heading %d", level)
+	s.Write(style, models.Alignments{Left: true}, markdownInline(text))
+	if x := s.PDF.GetX(); x > s.leftMargin() {
+		if sty, ok := s.style(style); ok {
+			s.PDF.Ln(sty.LineSize)
+		}
+	}
+}
+
+// markdownParagraph writes the lines of a paragraph, or the image it consists of, in the style.
+// A line ending in two spaces or a backslash breaks the line.
+func (s *SimPDF) markdownParagraph(lines []string, style models.Styles) {
+	if len(lines) == 1 {
+		if m := mdImageExp.FindStringSubmatch(strings.TrimSpace(lines[0])); m != nil {
+			s.markdownImage(m[2], style)
+			return
+		}
+	}
+	var b strings.Builder
+	for i, line := range lines {
+		text := strings.TrimLeft(line, " ")
+		switch {
+		case i == len(lines)-1:
+			b.WriteString(strings.TrimRight(text, " "))
+		case strings.HasSuffix(text, "  "):
+			b.WriteString(strings.TrimRight(text, " ") + "\n")
+		case strings.HasSuffix(text, "\\"):
+			b.WriteString(strings.TrimSuffix(text, "\\") + "\n")
+		default:
+			b.WriteString(strings.TrimRight(text, " ") + " ")
+		}
+	}
+	s.SetStyle(style, false)
+	s.writeSpans(style, "L", s.parseInline(markdownInline(b.String())))
+	s.PDF.Ln(style.LineSize)
+	s.PDF.Ln(style.LineSize / 2)
+}
+
+// markdownCode writes a fenced code block in the "Code" style, or a Courier version of the
+// body style, highlighted by the BackgroundColor of the style.
+func (s *SimPDF) markdownCode(code string, body models.Styles) {
+	fallback := body
+	fallback.Font.Name = "Courier"
+	style := s.markdownStyle("Code", fallback)
+	s.SetStyle(style, false)
+	s.PDF.SetX(s.leftMargin())
+	left, _, right, _ := s.PDF.GetMargins()
+	fill := style.BackgroundColor != (models.RGBColor{})
+	s.PDF.MultiCell(s.Width()-left-right, style.LineSize, code, "", "L", fill)
+	s.PDF.Ln(body.LineSize / 2)
+}
+
+// markdownRule writes a horizontal rule across the page.
+func (s *SimPDF) markdownRule(body models.Styles) {
+	if s.CheckBottom() {
+		s.Break()
+	}
+	y := s.PDF.GetY() + (body.LineSize / 2)
+	left, _, right, _ := s.PDF.GetMargins()
+	r, g, b := s.PDF.GetDrawColor()
+	lw := s.PDF.GetLineWidth()
+	s.PDF.SetDrawColor(int(colors.Grey.Red), int(colors.Grey.Green), int(colors.Grey.Blue))
+	s.PDF.SetLineWidth(1)
+	s.PDF.Line(left, y, s.Width()-right, y)
+	s.PDF.SetLineWidth(lw)
+	s.PDF.SetDrawColor(r, g, b)
+	s.PDF.SetXY(left, y+(body.LineSize/2))
+}
+
+// markdownQuote writes the lines of a block quote, themselves Markdown, indented with a bar
+// on the left in the "Quote" style, or an italic version of the body style. A quote continued
+// on the pages after has a bar on each page, between the header and the footer.
+func (s *SimPDF) markdownQuote(lines []string, body models.Styles) {
+	fallback := body
+	fallback.TextVariant.Italic = true
+	style := s.markdownStyle("Quote", fallback)
+	left := s.leftMargin()
+	first, top := s.PDF.PageNo(), s.PDF.GetY()
+	s.PDF.SetLeftMargin(left + mdIndent)
+	s.PDF.SetX(left + mdIndent)
+	s.renderMarkdown(lines, style)
+	s.PDF.SetLeftMargin(left)
+	last, bottom := s.PDF.PageNo(), s.PDF.GetY()-(style.LineSize/2)
+	x, y := s.PDF.GetXY()
+	r, g, b := s.PDF.GetDrawColor()
+	lw := s.PDF.GetLineWidth()
+	for page := first; page <= last; page++ {
+		from, to := top, bottom
+		if page != first {
+			from = s.contentTop(page)
+		}
+		if page != last {
+			to = s.pageBottom()
+		}
+		// The color and width are set on each page, as each page has its own drawing state.
+		s.PDF.SetPage(page)
+		s.PDF.SetDrawColor(int(colors.Grey.Red), int(colors.Grey.Green), int(colors.Grey.Blue))
+		s.PDF.SetLineWidth(3)
+		s.PDF.Line(left+(mdIndent/3), from, left+(mdIndent/3), to)
+	}
+	s.PDF.SetLineWidth(lw)
+	s.PDF.SetDrawColor(r, g, b)
+	s.PDF.SetXY(x, y)
+	s.PDF.SetX(left)
+}
+
+// parseMarkdownList returns the list starting at lines[i] and the index of the line after it.
+// Items indented further than the item before them are nested in it, and indented lines that
+// are not items continue the item before them.
+func parseMarkdownList(lines []string, i int) (*mdList, int) {
+	type level struct {
+		indent int
+		list   *mdList
+	}
+	var stack []level
+	var root *mdList
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			if i+1 < len(lines) && mdListItemExp.MatchString(lines[i+1]) {
+				continue
+			}
+			break
+		}
+		m := mdListItemExp.FindStringSubmatch(line)
+		if m == nil || mdRuleExp.MatchString(line) {
+			indent := len(line) - len(strings.TrimLeft(line, " "))
+			if indent == 0 || len(stack) == 0 {
+				break
+			}
+			items := stack[len(stack)-1].list.items
+			items[len(items)-1].text += " " + strings.TrimSpace(line)
+			continue
+		}
+		indent, marker := len(m[1]), m[2]
+		_, err := strconv.Atoi(strings.TrimRight(marker, ".)"))
+		for len(stack) > 0 && indent < stack[len(stack)-1].indent {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 1 && indent == stack[0].indent && stack[0].list.ordered != (err == nil) {
+			break
+		}
+		if len(stack) == 0 || indent > stack[len(stack)-1].indent {
+			list := &mdList{start: 1}
+			if n, err := strconv.Atoi(strings.TrimRight(marker, ".)")); err == nil {
+				list.ordered, list.start = true, n
+			}
+			if len(stack) == 0 {
+				if root != nil {
+					break
+				}
+				root = list
+			} else {
+				items := stack[len(stack)-1].list.items
+				items[len(items)-1].list = list
+			}
+			stack = append(stack, level{indent: indent, list: list})
+		}
+		list := stack[len(stack)-1].list
+		list.items = append(list.items, mdListItem{text: m[3]})
+	}
+	return root, i
+}
+
+// splitMarkdownRow returns the cells of a row of a pipe table. "\|" is a pipe in a cell.
+func splitMarkdownRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, "\\|") {
+		line = strings.TrimSuffix(line, "|")
+	}
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// markdownAlignments returns the table cell alignment of each delimiter of a pipe table.
+func markdownAlignments(delimiters []string) []string {
+	aligns := make([]string, len(delimiters))
+	for i, d := range delimiters {
+		switch {
+		case strings.HasPrefix(d, ":") && strings.HasSuffix(d, ":"):
+			aligns[i] = "C"
+		case strings.HasSuffix(d, ":"):
+			aligns[i] = "R"
+		default:
+			aligns[i] = "L"
+		}
+	}
+	return aligns
+}
+
+// markdownTable writes a pipe table, the first row being the header, through SimPDF.AddTable()
+// in the "Table" style, or the body style on white. Cells are written without their inline markup.
+func (s *SimPDF) markdownTable(rows [][]string, aligns []string, body models.Styles) {
+	rowStyle := s.markdownStyle("Table", body)
+	if rowStyle.BackgroundColor == (models.RGBColor{}) {
+		rowStyle.BackgroundColor = colors.White
+	}
+	headerStyle := rowStyle
+	headerStyle.TextVariant.Bold = true
+	cells := func(row []string) []string {
+		out := make([]string, len(aligns))
+		for i := range out {
+			text := ""
+			if i < len(row) {
+				text = s.inlineText(markdownInline(row[i]))
+			}
+			out[i] = aligns[i] + "**" + text
+		}
+		return out
+	}
+	table := Tables{
+		Headers:     cells(rows[0]),
+		HeaderStyle: headerStyle,
+		RowStyle:    rowStyle,
+	}
+	for _, row := range rows[1:] {
+		table.Rows = append(table.Rows, cells(row))
+	}
+	s.AddTable(table, models.Styles{}, 0)
+	s.PDF.Ln(body.LineSize / 2)
+}
+
+// markdownImage writes the image at path at its natural size, shrunk to fit between the
+// margins, starting a new page if it does not fit on this one.
+func (s *SimPDF) markdownImage(path string, body models.Styles) {
+	img, err := NewImage(path, 0, 0)
+	if err != nil {
+		s.SetError(err)
+		return
+	}
+	info := s.PDF.RegisterImageOptions(path, gofpdf.ImageOptions{ReadDpi: true})
+	if s.Err() {
+		return
+	}
+	left, _, right, _ := s.PDF.GetMargins()
+	img.Width, img.Height = info.Width(), info.Height()
+	if width := s.Width() - left - right; img.Width > width {
+		img.ChangeWidth(width)
+	}
+	bottom := s.pageBottom()
+	if s.PDF.GetY()+img.Height > bottom {
+		s.Break()
+	}
+	y := s.PDF.GetY()
+	s.AddImageXY(img, left, y)
+	s.PDF.SetXY(left, y+img.Height)
+	s.PDF.Ln(body.LineSize / 2)
+}
+
+// mdDelimiter is a run of "*" or "_" that may open or close emphasis in inline Markdown.
+type mdDelimiter struct {
+	piece  int
+	char   byte
+	strong bool
+	open   bool
+	close  bool
+}
+
+// markdownInline returns the inline Markdown of text as the inline markup of SimPDF.Parser().
+// **bold** and __bold__ are bold, *italic* and _italic_ are italic, `code` is in the "Code"
+// style, [text](url) and <https://example.com> are links, and an image is written as its alt
+// text. Characters that are markup to SimPDF.Parser() but not to Markdown are escaped.
+func markdownInline(text string) string {
+	var pieces []string
+	var delims []mdDelimiter
+	before := func(i int) rune {
+		if i == 0 {
+			return ' '
+		}
+		r, _ := utf8.DecodeLastRuneInString(text[:i])
+		return r
+	}
+	after := func(i int) rune {
+		if i >= len(text) {
+			return ' '
+		}
+		r, _ := utf8.DecodeRuneInString(text[i:])
+		return r
+	}
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		r, size := utf8.DecodeRuneInString(rest)
+		switch {
+		case r == '\\' && len(rest) > 1 && strings.IndexByte(mdPunctuation, rest[1]) >= 0:
+			pieces = append(pieces, escapeInline(rest[1:2]))
+			i += 2
+			continue
+		case r == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				pieces = append(pieces, "[[Code]]"+escapeInline(rest[1:end+1])+"[[/]]")
+				i += end + 2
+				continue
+			}
+		case r == '<':
+			if m := mdAutolinkExp.FindStringSubmatch(rest); m != nil {
+				pieces = append(pieces, "["+escapeInline(m[1])+"]("+m[1]+")")
+				i += len(m[0])
+				continue
+			}
+		case r == '!':
+			if m := mdInlineImageExp.FindStringSubmatch(rest); m != nil {
+				pieces = append(pieces, escapeInline(m[1]))
+				i += len(m[0])
+				continue
+			}
+		case r == '[':
+			if m := mdLinkExp.FindStringSubmatch(rest); m != nil {
+				pieces = append(pieces, "["+markdownInline(m[1])+"]("+m[2]+")")
+				i += len(m[0])
+				continue
+			}
+		case r == '*' || r == '_':
+			n := 1
+			if len(rest) > 1 && rest[1] == rest[0] {
+				n = 2
+			}
+			b, a := before(i), after(i+n)
+			d := mdDelimiter{piece: len(pieces), char: rest[0], strong: n == 2, open: !unicode.IsSpace(a), close: !unicode.IsSpace(b)}
+			if r == '_' {
+				d.open = d.open && !isWordRune(b)
+				d.close = d.close && !isWordRune(a)
+			}
+			delims = append(delims, d)
+			pieces = append(pieces, escapeInline(rest[:n]))
+			i += n
+			continue
+		case strings.HasPrefix(rest, "~~"):
+			pieces = append(pieces, "~~")
+			i += 2
+			continue
+		}
+		pieces = append(pieces, escapeInline(rest[:size]))
+		i += size
+	}
+	// pair each closing delimiter with the nearest opening delimiter of the same kind
+	var open []int
+	for i, d := range delims {
+		if d.close {
+			j := len(open) - 1
+			for ; j >= 0; j-- {
+				if o := delims[open[j]]; o.char == d.char && o.strong == d.strong {
+					break
+				}
+			}
+			if j >= 0 {
+				if d.strong {
+					pieces[delims[open[j]].piece], pieces[d.piece] = "__", "__"
+				} else {
+					pieces[delims[open[j]].piece], pieces[d.piece] = "_*", "*_"
+				}
+				open = open[:j]
+				continue
+			}
+		}
+		if d.open {
+			open = append(open, i)
+		}
+	}
+	return strings.Join(pieces, "")
+}
+
+// mdPunctuation are the characters a backslash escapes in Markdown.
+const mdPunctuation = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// escapeInline returns text with the markup characters of SimPDF.Parser() escaped.
+func escapeInline(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if strings.IndexByte(inlineEscapes, text[i]) >= 0 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(text[i])
+	}
+	return b.String()
+}
+
+// isWordRune returns true for a letter or digit, which an "_" within a word is not emphasis next to.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,140 @@
+package simpdf
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/braddschick/simpdf/internal"
+)
+
+// BackupPolicy decides what happens to an existing file when SimPDF.Finish() writes
+// over it.
+type BackupPolicy int
+
+const (
+	// BackupBak moves an existing file to the same path appended with ".bak". Any older
+	// ".bak" file is replaced. This is the default.
+	BackupBak BackupPolicy = iota
+	// BackupNone replaces an existing file without keeping a copy.
+	BackupNone
+	// BackupTimestamp moves an existing file to the same path appended with a timestamp
+	// and ".bak", example "report.pdf.20060102-150405.000000000.bak". SimPDF.BackupKeep
+	// limits how many of these are kept.
+	BackupTimestamp
+)
+
+// backupTimeLayout is the timestamp layout used by BackupTimestamp.
+const backupTimeLayout = "20060102-150405.000000000"
+
+// FinishTo ends the creation of the PDF document and writes it to w. Any error that
+// occurred while building the document is returned and nothing is written.
+// w remains open after this function returns.
+func (s *SimPDF) FinishTo(w io.Writer) error {
+	s.checkAnchors()
+	s.writeTableOfContents()
+	s.registerPageAliases()
+	return s.PDF.Output(w)
+}
+
+// Bytes ends the creation of the PDF document and returns it. Any error that occurred
+// while building the document is returned with a nil slice.
+func (s *SimPDF) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := s.FinishTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Finish ends the creation of the PDF document and saves it to a file as prescribed in fileOutput.
+// The document is written to a temporary file in the same directory and renamed over
+// fileOutput once complete, so a failed write never leaves a partial file behind.
+// If a file already exists at fileOutput it is kept as directed by SimPDF.Backup.
+func (s *SimPDF) Finish(fileOutput string) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(fileOutput), "."+filepath.Base(fileOutput)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// Once renamed the temporary file no longer exists and this is a no-op.
+	defer os.Remove(tmp.Name())
+	if err = s.FinishTo(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = s.backup(fileOutput); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), fileOutput)
+}
+
+// backup keeps the file currently at fileOutput as directed by SimPDF.Backup.
+func (s *SimPDF) backup(fileOutput string) error {
+	if s.Backup == BackupNone || !internal.ValidateFilePath(fileOutput) {
+		return nil
+	}
+	if s.Backup == BackupTimestamp {
+		stamped := fileOutput + "." + time.Now().Format(backupTimeLayout) + ".bak"
+		// A clock too coarse for the layout could repeat a stamp, never move over a backup.
+		for internal.ValidateFilePath(stamped) {
+			stamped = fileOutput + "." + time.Now().Format(backupTimeLayout) + ".bak"
+		}
+		if err := internal.MoveFilePath(fileOutput, stamped); err != nil {
+			return err
+		}
+		return s.pruneBackups(fileOutput)
+	}
+	return internal.MoveFilePath(fileOutput, fileOutput+".bak")
+}
+
+// pruneBackups removes the oldest timestamped backups of fileOutput so that no more than
+// SimPDF.BackupKeep remain. A BackupKeep of 0 keeps all of them.
+func (s *SimPDF) pruneBackups(fileOutput string) error {
+	if s.BackupKeep <= 0 {
+		return nil
+	}
+	dir, base := filepath.Split(fileOutput)
+	files, err := ioutil.ReadDir(filepath.Clean(dir))
+	if err != nil {
+		return err
+	}
+	type backup struct {
+		path  string
+		stamp time.Time
+	}
+	var olds []backup
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasPrefix(name, base+".") || !strings.HasSuffix(name, ".bak") {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, base+"."), ".bak")
+		if t, err := time.Parse(backupTimeLayout, stamp); err == nil && len(stamp) == len(backupTimeLayout) {
+			olds = append(olds, backup{path: filepath.Join(dir, name), stamp: t})
+		}
+	}
+	sort.Slice(olds, func(i, j int) bool { return olds[i].stamp.Before(olds[j].stamp) })
+	for len(olds) > s.BackupKeep {
+		if err := os.Remove(olds[0].path); err != nil {
+			return err
+		}
+		olds = olds[1:]
+	}
+	return nil
+}
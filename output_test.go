@@ -0,0 +1,54 @@
+package simpdf
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// TestPruneBackups checks that only the oldest timestamped backups of the output are removed.
+func TestPruneBackups(t *testing.T) {
+	dir, err := ioutil.TempDir("", "simpdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	names := []string{
+		"report.pdf.20200101-101010.000000001.bak",
+		"report.pdf.20200101-101010.900000000.bak",
+		"report.pdf.20200101-101011.000000000.bak",
+		"report.pdf.20200101-101010.bak",
+		"report.pdf.bak",
+		"other.pdf.20200101-101009.000000000.bak",
+		"20200101-101009.bak",
+	}
+	for _, name := range names {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := SimPDF{BackupKeep: 2}
+	if err := s.pruneBackups(filepath.Join(dir, "report.pdf")); err != nil {
+		t.Fatal(err)
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range files {
+		got = append(got, f.Name())
+	}
+	want := append([]string{}, names[1:]...)
+	sort.Strings(want)
+	if len(got) != len(want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("files = %v, want %v", got, want)
+		}
+	}
+}
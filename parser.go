@@ -2,28 +2,41 @@ package simpdf
 
 import (
 	"regexp"
-	"strings"
 
 	"github.com/braddschick/simpdf/pkg/models"
 )
 
+// The expressions and substitutions below were the built-in inline markup before
+// SimPDF.Parser() paired its markers itself. They are kept for code that uses them.
 var (
 	// BoldExp checks to see if the double underscore was used to denote bold for text
+	//
+	// Deprecated: SimPDF.Parser() no longer uses it.
 	BoldExp = regexp.MustCompile(`(?m)(?:\_\_([\S]+[^\_\_]*)\_\_)`)
 	// BoldSub is the html for bolding within group matching
+	//
+	// Deprecated: SimPDF.Parser() no longer writes HTML.
 	BoldSub = "<b>$1</b>"
-	// StrikeExp is denoted by ~~strike through text~~
-	// StrikeExp = regexp.MustCompile(`(?m)(?:\~\~([\S]+[^\~]*)\~\~))`)
 	// ItalicExp is denoted by _*italic text*_
+	//
+	// Deprecated: SimPDF.Parser() no longer uses it.
 	ItalicExp = regexp.MustCompile(`(?m)(?:\_\*([\S]+[^\_\*]*)\*\_)`)
 	// ItalicSub is the html for italics within group matching
+	//
+	// Deprecated: SimPDF.Parser() no longer writes HTML.
 	ItalicSub = "<i>$1</i>"
 	// UnderlineExp is denoted by _#underlined text#_
+	//
+	// Deprecated: SimPDF.Parser() no longer uses it.
 	UnderlineExp = regexp.MustCompile(`(?m)(?:\_\#([\S]+[^\_\#]*)\#\_)`)
 	// UnderlineSub is the html for underlinging within group matching
+	//
+	// Deprecated: SimPDF.Parser() no longer writes HTML.
 	UnderlineSub = "<u>$1</u>"
 )
 
+// Expressions is a rule of inline markup registered by SimPDF.RegisterMarkup(). Parse
+// returns text with the markup of the rule rewritten, and true if there was any.
 type Expressions interface {
 	IsMatch(text string) bool
 	Parse(text string) (string, bool)
@@ -49,8 +62,13 @@ func (p *ParseGroup) ReplaceAll(text string) string {
 }
 
 // Parse will replace all matches with proper susbstitution group. This is used
-// for replacing the "markdown-like" structures with the proper HTML code for
-// rendering.
+// for rewriting domain markup into the built-in markup of SimPDF.Parser(), example
+// {{ticket:ABC-123}} into a link:
+//
+//	pdf.RegisterMarkup("ticket", &simpdf.ParseGroup{
+//		Expression:   *regexp.MustCompile(`\{\{ticket:([A-Z]+-[0-9]+)\}\}`),
+//		Substitution: "[$1](https://tickets.example.com/$1)",
+//	})
 func (p *ParseGroup) Parse(text string) (string, bool) {
 	if p.IsMatch(text) {
 		return p.ReplaceAll(text), true
@@ -58,49 +76,132 @@ func (p *ParseGroup) Parse(text string) (string, bool) {
 	return text, false
 }
 
-func createParseGroup() []ParseGroup {
-	var pGroup []ParseGroup
-	pGroup = append(pGroup, ParseGroup{
-		Expression:   *BoldExp,
-		Substitution: BoldSub,
-	})
-	pGroup = append(pGroup, ParseGroup{
-		Expression:   *ItalicExp,
-		Substitution: ItalicSub,
-	})
-	pGroup = append(pGroup, ParseGroup{
-		Expression:   *UnderlineExp,
-		Substitution: UnderlineSub,
-	})
-	return pGroup
+// StyleGroup writes the text matched by the regular expression in the named style, example
+// !!text!! in a "Warning" style:
+//
+//	pdf.RegisterMarkup("warning", &simpdf.StyleGroup{
+//		Expression: *regexp.MustCompile(`!!(.+?)!!`),
+//		Style:      "Warning",
+//	})
+//
+// The style is used the same way as [[Name]]text[[/]], see SimPDF.Parser(). The first group of the Expression is
+// the text written, or the whole match if there is no group.
+type StyleGroup struct {
+	Expression regexp.Regexp
+	Style      string
+}
+
+// IsMatch checks if the simpdf.StyleGroup matches the variable text.
+func (p *StyleGroup) IsMatch(text string) bool {
+	return p.Expression.MatchString(text)
+}
+
+// ReplaceAll replaces all occurrences of the simpdf.StyleGroup with its text marked to be
+// written in the simpdf.StyleGroup.Style.
+func (p *StyleGroup) ReplaceAll(text string) string {
+	group := "${0}"
+	if p.Expression.NumSubexp() > 0 {
+		group = "${1}"
+	}
+	return p.Expression.ReplaceAllString(text, styleStart(p.Style)+group+styleEnd)
+}
+
+// Parse will replace all matches with their text marked to be written in the style.
+func (p *StyleGroup) Parse(text string) (string, bool) {
+	if p.IsMatch(text) {
+		return p.ReplaceAll(text), true
+	}
+	return text, false
+}
+
+// markupRule is a rule of inline markup registered by SimPDF.RegisterMarkup().
+type markupRule struct {
+	name string
+	rule Expressions
+}
+
+// RegisterMarkup adds the rule to the inline markup of SimPDF.Parser() by name, replacing the
+// rule of that name if already registered. Registered rules rewrite the text in the order
+// registered, before the built-in markup is applied, so a rule may rewrite its markup into
+// the built-in markup. See simpdf.ParseGroup and simpdf.StyleGroup.
+func (s *SimPDF) RegisterMarkup(name string, rule Expressions) {
+	for i := range s.markup {
+		if s.markup[i].name == name {
+			s.markup[i].rule = rule
+			return
+		}
+	}
+	s.markup = append(s.markup, markupRule{name: name, rule: rule})
+}
+
+// DisableMarkup turns off the inline markup of the names given, so it is written as is.
+// Names are those given to SimPDF.RegisterMarkup() or the built-in "bold", "italic",
+// "underline", "strike", "superscript", "subscript", "link", and "style".
+func (s *SimPDF) DisableMarkup(names ...string) {
+	if s.disabledMarkup == nil {
+		s.disabledMarkup = make(map[string]bool)
+	}
+	for _, name := range names {
+		s.disabledMarkup[name] = true
+	}
+}
+
+// EnableMarkup turns the inline markup of the names given back on. See SimPDF.DisableMarkup().
+func (s *SimPDF) EnableMarkup(names ...string) {
+	for _, name := range names {
+		delete(s.disabledMarkup, name)
+	}
+}
+
+// parseInline splits text into spans by the registered rules and built-in inline markup.
+func (s *SimPDF) parseInline(text string) []span {
+	for _, m := range s.markup {
+		if !s.disabledMarkup[m.name] {
+			text, _ = m.rule.Parse(text)
+		}
+	}
+	tokens := lexInline(text, s.disabledMarkup)
+	pairInline(tokens)
+	return buildSpans(tokens)
+}
+
+// inlineText returns text without its inline markup.
+func (s *SimPDF) inlineText(text string) string {
+	return joinSpans(s.parseInline(text))
 }
 
 // Parser this checks if there is any Markdown like style requirements in the
-// text to be replaced by HTML that gofpdf.PDF understands.
+// text and writes it to the PDF document when there is.
 //
 // __text__ => Bold text
 // _*text*_ => Italic text
 // _#text#_ => Underline text
+// ~~text~~ => Strike through text
+// ^text^ => Superscript text, without spaces, example x^2^
+// ~text~ => Subscript text, without spaces, example H~2~O
+// [text](https://example.com) => External link
+// [text](#name) => Link to SimPDF.Anchor("name")
+// [[Name]]text[[/]] => Text in the style "Name"
+//
+// A named style uses the font, text size, color, and BackgroundColor of the style, the
+// latter highlighting the text unless left as black. Bold and the like are added to the
+// style, example [[Code]]__bold code__[[/]]. A name not in SimPDF.Style is "Normal".
 //
-// Return string of the text orginally assigned if it does not contain any MD,
-// string will be empty if it did contain any MD to be transformed.
+// Markup may be nested, example __bold _*and italic*___, and a backslash writes the
+// character after it as is, example \_\_init\_\_. Markup left open is written as is.
+//
+// Return string of the text orginally assigned, with escapes removed, if it does not
+// contain any MD, string will be empty if it did contain any MD to be transformed.
 func (s *SimPDF) Parser(style string, align models.Alignments, text string) string {
-	pGroup := createParseGroup()
-	out := text
-	needHTML := false
-	test := false
-	for _, p := range pGroup {
-		out, test = p.Parse(out)
-		if test {
-			needHTML = true
-		}
+	spans := s.parseInline(text)
+	if !formatted(spans) {
+		return joinSpans(spans)
 	}
-	if needHTML {
-		html := s.PDF.HTMLBasicNew()
-		sty, _ := s.StyleName(style)
-		s.SetStyle(sty, false)
-		html.Write(sty.LineSize, strings.Replace(align.ToHTML(), "$1", out, -1))
+	sty, ok := s.style(style)
+	if !ok {
 		return ""
 	}
-	return text
+	s.SetStyle(sty, false)
+	s.writeSpans(sty, align.ToPDF(), spans)
+	return ""
 }
@@ -0,0 +1,19 @@
+package models
+
+// Aggregates is a calculation over the values of a column of a table, such as its total.
+type Aggregates int
+
+const (
+	// NoAggregate leaves the column without a calculation.
+	NoAggregate Aggregates = iota
+	// Sum is the total of the numbers of the column.
+	Sum
+	// Average is the mean of the numbers of the column.
+	Average
+	// Count is the number of cells of the column that are not empty.
+	Count
+	// Minimum is the least of the numbers of the column.
+	Minimum
+	// Maximum is the greatest of the numbers of the column.
+	Maximum
+)
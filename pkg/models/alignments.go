@@ -10,6 +10,11 @@ type Alignments struct {
 	Justify bool
 }
 
+// Empty function returns true if no alignment has been marked true.
+func (a *Alignments) Empty() bool {
+	return !a.Center && !a.Left && !a.Right && !a.Justify
+}
+
 // ToPDF function returns the correct string character for the gofpdf.PDF
 // document.
 func (a *Alignments) ToPDF() string {
@@ -10,8 +10,54 @@ type BorderWidths struct {
 	Unit   Units
 }
 
+// BorderColors struct allows for the notation of the color of each side of a border. A side
+// left as is, black, uses the Borders.Color.
+type BorderColors struct {
+	Left   RGBColor
+	Top    RGBColor
+	Right  RGBColor
+	Bottom RGBColor
+}
+
 // Borders struct is the object for use in models.Styles
 type Borders struct {
-	Color RGBColor
-	Width BorderWidths
+	Color  RGBColor
+	Width  BorderWidths
+	Colors BorderColors
+}
+
+// Side function returns the width and color of the side of the border, one of "L" left,
+// "T" top, "R" right, or "B" bottom.
+func (b *Borders) Side(side string) (float64, RGBColor) {
+	width, color := b.Width.Left, b.Colors.Left
+	switch side {
+	case "T":
+		width, color = b.Width.Top, b.Colors.Top
+	case "R":
+		width, color = b.Width.Right, b.Colors.Right
+	case "B":
+		width, color = b.Width.Bottom, b.Colors.Bottom
+	}
+	if color == (RGBColor{}) {
+		color = b.Color
+	}
+	return width, color
 }
+
+// TableBorders is the set of lines drawn around the cells of a table.
+type TableBorders int
+
+const (
+	// StyleBorders draws the sides of each cell that have a width in the Border of its style.
+	StyleBorders TableBorders = iota
+	// GridBorders draws every side of every cell.
+	GridBorders
+	// HorizontalBorders draws the top and bottom of every cell.
+	HorizontalBorders
+	// OuterBorders draws a box around the table.
+	OuterBorders
+	// HeaderUnderline draws a line below the header rows.
+	HeaderUnderline
+	// NoBorders draws no lines.
+	NoBorders
+)
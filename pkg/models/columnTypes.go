@@ -0,0 +1,24 @@
+package models
+
+// ColumnTypes is the type of the values of a column of a table.
+type ColumnTypes int
+
+const (
+	// TextColumn values are written as they are.
+	TextColumn ColumnTypes = iota
+	// IntColumn values are whole numbers, 1,200.
+	IntColumn
+	// DecimalColumn values are numbers with decimal places, 1,200.50.
+	DecimalColumn
+	// CurrencyColumn values are amounts of money, $1,200.50.
+	CurrencyColumn
+	// PercentColumn values are fractions written as percentages, 0.125 is 12.5%.
+	PercentColumn
+	// DateColumn values are dates and times.
+	DateColumn
+)
+
+// Numeric function returns true for the types of numbers.
+func (c ColumnTypes) Numeric() bool {
+	return c == IntColumn || c == DecimalColumn || c == CurrencyColumn || c == PercentColumn
+}
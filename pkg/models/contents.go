@@ -1,28 +1,31 @@
 package models
 
 import (
-	"strconv"
 	"strings"
 )
 
+// Contents struct is the text of a header or footer position.
+// If PageNumber is true the page number is added after the Text, or before it if
+// Prepend is true. Numbering describes how the page number is written.
 type Contents struct {
 	PageNumber bool
 	Align      Alignments
 	Prepend    bool
 	Text       string
 	Style      Styles
+	Numbering  PageNumbers
 }
 
 func (c *Contents) Empty() bool {
-	return strings.Trim(c.Text, " ") == ""
+	return strings.Trim(c.Text, " ") == "" && !c.PageNumber
 }
 
 func (c *Contents) AddPage(pg int) string {
 	if c.PageNumber {
 		if c.Prepend {
-			return strconv.Itoa(pg) + " " + c.Text
+			return strings.TrimSpace(c.Numbering.Label(pg) + " " + c.Text)
 		}
-		return c.Text + " " + strconv.Itoa(pg)
+		return strings.TrimSpace(c.Text + " " + c.Numbering.Label(pg))
 	}
 	return c.Text
 }
@@ -1,9 +1,126 @@
 package models
 
-// type PageNumbers struct {
-// 	PageSingle string
-// 	PageOf string
-// 	Separator Borders
-// 	IncludeTotal bool
-// 	Style Styles
-// }
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NumberStyles is the style used to write a number such as a page number.
+type NumberStyles int
+
+const (
+	// Arabic numbers 1, 2, 3, ...
+	Arabic NumberStyles = iota
+	// LowerRoman numbers i, ii, iii, ...
+	LowerRoman
+	// UpperRoman numbers I, II, III, ...
+	UpperRoman
+	// LowerLetter numbers a, b, c, ... z, aa, ab, ...
+	LowerLetter
+	// UpperLetter numbers A, B, C, ... Z, AA, AB, ...
+	UpperLetter
+)
+
+// Format function returns num written in the NumberStyles. Numbers below 1 cannot be
+// written as roman numerals or letters and are always written in arabic numbers.
+func (n NumberStyles) Format(num int) string {
+	if num < 1 {
+		return strconv.Itoa(num)
+	}
+	switch n {
+	case LowerRoman:
+		return strings.ToLower(toRoman(num))
+	case UpperRoman:
+		return toRoman(num)
+	case LowerLetter:
+		return strings.ToLower(toLetters(num))
+	case UpperLetter:
+		return toLetters(num)
+	}
+	return strconv.Itoa(num)
+}
+
+var romanNumerals = []struct {
+	value  int
+	symbol string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+	{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+	{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+func toRoman(num int) string {
+	var b strings.Builder
+	for _, r := range romanNumerals {
+		for num >= r.value {
+			b.WriteString(r.symbol)
+			num -= r.value
+		}
+	}
+	return b.String()
+}
+
+func toLetters(num int) string {
+	var out []byte
+	for num > 0 {
+		num--
+		out = append([]byte{byte('A' + num%26)}, out...)
+		num /= 26
+	}
+	return string(out)
+}
+
+// PageNumbers struct describes how the page number of a models.Contents is written.
+type PageNumbers struct {
+	// Format is the text written for the page number. "{page}" is replaced by the page
+	// number and "{pages}" by the total number of pages, example "Page {page} of {pages}".
+	// An empty Format is the same as "{page}".
+	Format string
+	// Style is the NumberStyles used for both "{page}" and "{pages}".
+	Style NumberStyles
+	// Start is the number written on the first page. 0 is the same as 1.
+	Start int
+	// Separator is a line drawn across the page between the page contents and the header or
+	// footer holding the page number. Width.Bottom is used below a header and Width.Top
+	// above a footer.
+	Separator Borders
+}
+
+// Number function returns the page number of page pg, the first page being 1, after the
+// Start offset has been applied.
+func (p *PageNumbers) Number(pg int) int {
+	if p.Start == 0 {
+		return pg
+	}
+	return pg + p.Start - 1
+}
+
+// TotalAlias function returns the text written in place of "{pages}". It is replaced by
+// the total number of pages as the PDF document is closed. The plain arabic total uses
+// the gofpdf.Fpdf.AliasNbPages() default of "{nb}".
+func (p *PageNumbers) TotalAlias() string {
+	if p.Style == Arabic && p.Number(1) == 1 {
+		return "{nb}"
+	}
+	return fmt.Sprintf("{nb:%d:%d}", p.Style, p.Number(1))
+}
+
+// Total function returns the total number of pages written the way TotalAlias is to be
+// replaced for a document of pages pages.
+func (p *PageNumbers) Total(pages int) string {
+	return p.Style.Format(p.Number(pages))
+}
+
+// Label function returns the Format for page pg with "{page}" replaced. "{pages}" is
+// replaced by the TotalAlias.
+func (p *PageNumbers) Label(pg int) string {
+	format := p.Format
+	if format == "" {
+		format = "{page}"
+	}
+	return strings.NewReplacer(
+		"{page}", p.Style.Format(p.Number(pg)),
+		"{pages}", p.TotalAlias(),
+	).Replace(format)
+}
@@ -1,6 +1,7 @@
 package simpdf
 
 import (
+	"io"
 	"time"
 
 	"github.com/braddschick/simpdf/pkg/models"
@@ -9,23 +10,34 @@ import (
 
 // Doc is the interface used by the simpdf.SimPDF
 type Doc interface {
+	AddBookmark(text string, level int)
 	AddBottomLine(style models.Styles)
 	AddImageCurrent(image Images)
 	AddImageStandardPosition(image Images, stdPosition string)
 	AddImageXY(image Images, x, y float64)
+	AddList(style string, items []ListItem, ordered bool)
 	AddMargins(margin models.Margins)
 	AddStyle(style []models.Styles)
+	AddTableOfContents(style string)
 	AddTable(tab Tables, altRowColor models.Styles, fixedWidth float64)
 	AddTableHeader(table Tables, fixWidth float64)
 	AddTableRows(table Tables, fixWidth float64)
+	Anchor(name string)
 	AppendStyle(style models.Styles)
 	Break()
+	Build(build func(pdf *SimPDF)) error
 	Font(style models.Styles)
 	NewPage(page models.Pages)
 	CheckBottom() bool
+	DisableMarkup(names ...string)
 	DistributeColumnsEvenly(numCols float64) float64
 	DrawBottomLine(style models.Styles)
-	Finish(fileOutput string)
+	EnableMarkup(names ...string)
+	Err() bool
+	Error() error
+	Finish(fileOutput string) error
+	FinishTo(w io.Writer) error
+	Bytes() ([]byte, error)
 	HeadingEnd(styleType string)
 	HeadingStart(styleType, text string)
 	NewLine()
@@ -33,11 +45,14 @@ type Doc interface {
 	Width() float64
 	Parser(style string, align models.Alignments, text string) string
 	NewFont(fontFilePath string) error
+	RegisterMarkup(name string, rule Expressions)
 	SetMargin(margin models.Margins)
+	SetFooter(footer HeaderFooters)
+	SetHeader(header HeaderFooters)
 	SetPage(pageType string, isLandscape bool)
 	SetStyle(style models.Styles, fontOnly bool)
 	StandardPosition(position string) (float64, float64)
-	Start(pageType string, isLandscape bool, styles []models.Styles, margin models.Margins, customFontDirectory string)
+	Start(pageType string, isLandscape bool, styles []models.Styles, margin models.Margins, customFontDirectory string) error
 	StringWidth(text string) float64
 	StyleName(name string) (models.Styles, error)
 	TableColumnWidth(table Tables) []float64
@@ -65,4 +80,48 @@ type SimPDF struct {
 	Subject string
 	// CreationDate gets authomatically set by running
 	CreationDate time.Time
+	// Header is written at the top of every page. Use SimPDF.SetHeader() to set it.
+	Header HeaderFooters
+	// Footer is written at the bottom of every page. Use SimPDF.SetFooter() to set it.
+	Footer HeaderFooters
+	// Backup decides what SimPDF.Finish() does with a file already at the output path.
+	// Defaults to BackupBak.
+	Backup BackupPolicy
+	// BackupKeep is the number of timestamped backups kept when Backup is BackupTimestamp.
+	// 0 keeps all of them.
+	BackupKeep int
+	// ListBullets are the bullets of unordered lists by level. Defaults to DefaultListBullets.
+	ListBullets []string
+	// ListNumbering is the numbering of ordered lists by level. Defaults to DefaultListNumbering.
+	ListNumbering []models.NumberStyles
+	// ListIndent is how far each level of a list is indented in pts. Defaults to DefaultListIndent.
+	ListIndent float64
+	// variables holds the header and footer variables registered by SimPDF.SetVariable()
+	variables map[string]func() string
+	// outlineDepth is one more than the level of the last bookmark added by
+	// SimPDF.AddBookmark(), 0 when there is none yet
+	outlineDepth int
+	// toc is the table of contents reserved by SimPDF.ReserveTableOfContents(), if any
+	toc *tableOfContents
+	// tocReserve is the number of pages the table of contents needs, found by SimPDF.Build()
+	tocReserve int
+	// headings are the headings written so far, for the table of contents
+	headings []tocEntry
+	// links are the gofpdf links of the anchors by name, and anchors those set by SimPDF.Anchor()
+	links   map[string]int
+	anchors map[string]bool
+	// section is the text of the last "Title" or "Heading 1" written, the "{section}" variable
+	section string
+	// markup are the rules of inline markup registered by SimPDF.RegisterMarkup()
+	markup []markupRule
+	// disabledMarkup are the names of the inline markup turned off by SimPDF.DisableMarkup()
+	disabledMarkup map[string]bool
+	// cp1252 translates UTF-8 text for the core fonts, see SimPDF.fontText()
+	cp1252 func(string) string
+	// headerPage is the last page the header has been written to
+	headerPage int
+	// footerBand is the height from the bottom of the page taken by the footer
+	footerBand float64
+	// err holds the document error until SimPDF.PDF has been created by SimPDF.Start()
+	err error
 }
@@ -0,0 +1,147 @@
+package simpdf
+
+import (
+	"github.com/braddschick/simpdf/pkg/models"
+)
+
+// TableCell struct is a cell of a table. It is the richer alternative to the alignment**content
+// format of Tables.Headers and Tables.Rows, allowing a cell to span columns and rows and to hold
+// an image or a nested table. The Align of the cell applies to all of its contents.
+type TableCell struct {
+	// Text of the cell.
+	Text string
+	// Value of the cell in a typed column of Tables.ColumnFormats, written as the Text. A cell
+	// without a Value has it read from the Text.
+	Value interface{}
+	// Align is the "L" left, "C" center, or "R" right alignment of the Text. Empty is "L".
+	Align string
+	// ColSpan is the number of columns the cell spans to the right. 0 is the same as 1.
+	ColSpan int
+	// RowSpan is the number of rows the cell spans downward. 0 is the same as 1. A cell does not
+	// span past the last row of the header, or of the body, it is part of.
+	RowSpan int
+	// Image is written below the Text when its FilePath is set, shrunk to fit the width of the
+	// cell. An image without a Width or Height is its natural size.
+	Image Images
+	// Table is a table nested in the cell below the Text and Image. It is kept within the width
+	// of the cell and is not split between pages.
+	Table *Tables
+	// VerticalAlign is the "T" top, "M" middle, or "B" bottom alignment of the Text in the rows
+	// the cell spans. Empty is the Tables.VerticalAlign.
+	VerticalAlign string
+	// Style replaces every other style of the cell when it has a Name.
+	Style models.Styles
+	// summary is true for a cell of a group header or subtotal row of Tables.HasGroups, which
+	// takes no Tables.Conditions or Tables.ColumnStyles.
+	summary bool
+	// dataRow is the index of the row of a body cell among the data rows, not counting the
+	// group header and subtotal rows.
+	dataRow int
+}
+
+// NewTableCell returns the cell of str in the alignment**content format, example "C**Q1".
+func NewTableCell(str string) TableCell {
+	align, text := BreakTableAlignment(str)
+	return TableCell{Text: text, Align: align}
+}
+
+// tableCells returns rows in the alignment**content format as TableCell rows.
+func tableCells(rows [][]string) [][]TableCell {
+	out := make([][]TableCell, len(rows))
+	for i, row := range rows {
+		out[i] = make([]TableCell, len(row))
+		for j, str := range row {
+			out[i][j] = NewTableCell(str)
+		}
+	}
+	return out
+}
+
+// placedCell is a cell of a table placed at the row and column it starts in.
+type placedCell struct {
+	TableCell
+	row, col int
+}
+
+// placeCells places the cells of rows in columns, left to right, skipping the columns taken by
+// cells spanning down from the rows above. Spans are clamped to at least 1 and to the last
+// row. The number of columns taken is also returned.
+func placeCells(rows [][]TableCell) ([]placedCell, int) {
+	var placed []placedCell
+	taken := make([]map[int]bool, len(rows))
+	for i := range taken {
+		taken[i] = make(map[int]bool)
+	}
+	cols := 0
+	for r, row := range rows {
+		col := 0
+		for _, cell := range row {
+			for taken[r][col] {
+				col++
+			}
+			if cell.ColSpan < 1 {
+				cell.ColSpan = 1
+			}
+			if cell.RowSpan < 1 {
+				cell.RowSpan = 1
+			}
+			if r+cell.RowSpan > len(rows) {
+				cell.RowSpan = len(rows) - r
+			}
+			for dr := 0; dr < cell.RowSpan; dr++ {
+				for dc := 0; dc < cell.ColSpan; dc++ {
+					taken[r+dr][col+dc] = true
+				}
+			}
+			placed = append(placed, placedCell{TableCell: cell, row: r, col: col})
+			col += cell.ColSpan
+		}
+		for c := range taken[r] {
+			if c+1 > cols {
+				cols = c + 1
+			}
+		}
+	}
+	return placed, cols
+}
+
+// nestedTable is a table laid out in a cell of another table.
+type nestedTable struct {
+	widths []float64
+	parts  []tableLayout
+}
+
+// layoutNestedTable lays out the header, body, and footer rows of the table within width.
+func (s *SimPDF) layoutNestedTable(table Tables, width float64) *nestedTable {
+	table.MaxColWidth = s.tableColumnWidths(table, width)
+	nested := &nestedTable{widths: table.columnWidths(0)}
+	for _, part := range []tableParts{headerPart, bodyPart, footerPart} {
+		if layout := s.layoutTable(table, part, nested.widths); len(layout.heights) > 0 {
+			nested.parts = append(nested.parts, layout)
+		}
+	}
+	return nested
+}
+
+// cellImage returns the image sized for a cell, its natural size when it has no Width or
+// Height, shrunk to width. An image file that cannot be accessed is recorded as an error
+// wrapping ErrImageNotFound.
+func (s *SimPDF) cellImage(img Images, width float64) Images {
+	img = s.imageSize(img)
+	if img.Width > width {
+		img.ChangeWidth(width)
+	}
+	return img
+}
+
+// alignX returns the X position of content as wide as width in the width from x in the "L"
+// left, "C" center, or "R" right alignment.
+func alignX(x, width, content float64, align string) float64 {
+	switch align {
+	case "C":
+		return x + (width-content)/2
+	case "R":
+		return x + width - content
+	}
+	return x
+}
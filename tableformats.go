@@ -0,0 +1,200 @@
+package simpdf
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/braddschick/simpdf/pkg/models"
+)
+
+// ColumnFormats struct is the type of the values of a column of a table and how they are
+// written. The data cells of a column with a Type other than models.TextColumn have their Value,
+// or else their Text, read as the Type and written in the format. Numbers are right aligned with
+// their decimal points lined up, and text that cannot be read is written as it is.
+type ColumnFormats struct {
+	// Type of the values of the column.
+	Type models.ColumnTypes
+	// Decimals is the number of decimal places of a decimal, currency, or percent column.
+	Decimals int
+	// Thousands is the separator between each three digits of a number, example ",". Empty
+	// writes no separator.
+	Thousands string
+	// DecimalPoint is written between a number and its decimal places. Empty is ".".
+	DecimalPoint string
+	// Symbol is the currency symbol of a currency column, written as it is. Empty is "$".
+	Symbol string
+	// SymbolAfter writes the Symbol after the number rather than before it.
+	SymbolAfter bool
+	// Parentheses writes negative numbers in parentheses, (1,200.00), rather than with a minus.
+	Parentheses bool
+	// Layout is the time.Format layout of a date column, also used to read the dates of the cell
+	// text. Empty is "2006-01-02".
+	Layout string
+}
+
+// columnFormat returns the ColumnFormats of the column at index, and false if it is not typed.
+func (t *Tables) columnFormat(index int) (ColumnFormats, bool) {
+	if index < len(t.ColumnFormats) && t.ColumnFormats[index].Type != models.TextColumn {
+		return t.ColumnFormats[index], true
+	}
+	return ColumnFormats{}, false
+}
+
+// typedRows returns the rows with the cells of the typed columns given the Value read from
+// them and written as the Text in the format of the column. Numbers are right aligned.
+func (t *Tables) typedRows(rows [][]TableCell) [][]TableCell {
+	if len(t.ColumnFormats) == 0 {
+		return rows
+	}
+	placed, _ := placeCells(rows)
+	out := make([][]TableCell, len(rows))
+	next := 0
+	for r, row := range rows {
+		out[r] = make([]TableCell, len(row))
+		for i, cell := range row {
+			col := placed[next].col
+			next++
+			if f, ok := t.columnFormat(col); ok && placed[next-1].ColSpan == 1 {
+				if value, ok := f.read(cell); ok {
+					cell.Value, cell.Text = value, f.write(value)
+				}
+				if f.Type.Numeric() {
+					cell.Align = "R"
+				}
+			}
+			out[r][i] = cell
+		}
+	}
+	return out
+}
+
+// read returns the value of the cell, its Value or else its Text, as the Type of the column.
+// Numbers are float64 and dates time.Time. ok is false when there is no value of the Type.
+func (f ColumnFormats) read(cell TableCell) (value interface{}, ok bool) {
+	v := driverValue(cell.Value)
+	if v == nil {
+		v = cell.Text
+	}
+	if text, isText := v.(string); isText {
+		if strings.TrimSpace(text) == "" {
+			return nil, false
+		}
+		if f.Type == models.DateColumn {
+			return f.readDate(text)
+		}
+		return f.readNumber(text)
+	}
+	if f.Type == models.DateColumn {
+		date, ok := v.(time.Time)
+		return date, ok
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return nil, false
+}
+
+// readNumber returns the number of the text written with or without the separators, symbol,
+// and parentheses of the format. The text of a percent is its percentage, "12.5" is 0.125.
+func (f ColumnFormats) readNumber(text string) (interface{}, bool) {
+	text = strings.Replace(strings.Replace(text, f.symbol(), "", -1), "%", "", -1)
+	if f.Thousands != "" {
+		text = strings.Replace(text, f.Thousands, "", -1)
+	}
+	if f.DecimalPoint != "" && f.DecimalPoint != "." {
+		text = strings.Replace(text, f.DecimalPoint, ".", -1)
+	}
+	value, _, ok := parseNumber(text)
+	if !ok {
+		return nil, false
+	}
+	if f.Type == models.PercentColumn {
+		value /= 100
+	}
+	return value, true
+}
+
+// readDate returns the date of the text in the Layout, "2006-01-02", or time.RFC3339.
+func (f ColumnFormats) readDate(text string) (interface{}, bool) {
+	text = strings.TrimSpace(text)
+	for _, layout := range []string{f.layout(), "2006-01-02", time.RFC3339} {
+		if date, err := time.Parse(layout, text); err == nil {
+			return date, true
+		}
+	}
+	return nil, false
+}
+
+// write returns the text of a value read by ColumnFormats.read().
+func (f ColumnFormats) write(value interface{}) string {
+	if date, ok := value.(time.Time); ok {
+		return date.Format(f.layout())
+	}
+	v, _ := value.(float64)
+	decimals := f.Decimals
+	if f.Type == models.IntColumn || decimals < 0 {
+		decimals = 0
+	}
+	if f.Type == models.PercentColumn {
+		v *= 100
+	}
+	digits := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	negative := v < 0 && strings.Trim(digits, "0.") != ""
+	whole, fraction := digits, ""
+	if i := strings.Index(digits, "."); i >= 0 {
+		whole, fraction = digits[:i], digits[i+1:]
+	}
+	if f.Thousands != "" {
+		for i := len(whole) - 3; i > 0; i -= 3 {
+			whole = whole[:i] + f.Thousands + whole[i:]
+		}
+	}
+	text := whole
+	if fraction != "" {
+		point := f.DecimalPoint
+		if point == "" {
+			point = "."
+		}
+		text += point + fraction
+	}
+	switch {
+	case f.Type == models.PercentColumn:
+		text += "%"
+	case f.Type == models.CurrencyColumn && f.SymbolAfter:
+		text += f.symbol()
+	case f.Type == models.CurrencyColumn:
+		text = f.symbol() + text
+	}
+	if !negative {
+		return text
+	}
+	if f.Parentheses {
+		return "(" + text + ")"
+	}
+	return "-" + text
+}
+
+// symbol returns the currency Symbol, "$" if it is empty.
+func (f ColumnFormats) symbol() string {
+	if f.Symbol == "" {
+		return "$"
+	}
+	return f.Symbol
+}
+
+// layout returns the date Layout, "2006-01-02" if it is empty.
+func (f ColumnFormats) layout() string {
+	if f.Layout == "" {
+		return "2006-01-02"
+	}
+	return f.Layout
+}
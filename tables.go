@@ -1,11 +1,9 @@
 package simpdf
 
 import (
-	"fmt"
 	"math"
 	"strings"
 
-	"github.com/braddschick/simpdf/internal"
 	"github.com/braddschick/simpdf/pkg/models"
 )
 
@@ -25,41 +23,301 @@ type Tables struct {
 	HasAlternating bool
 	// AlternatingRowStyle contains the models.Styles that will depict each even data row.
 	AlternatingRowStyle models.Styles
+	// ColumnFormats contains the type and format of the values of the data cells of each column,
+	// by index. The typed values are used by the Totals and given to the Conditions.
+	ColumnFormats []ColumnFormats
+	// ColumnWidths contains the width of each column, by index, in place of the width of its
+	// contents. It is not used when AddTable() is given a fixed width.
+	ColumnWidths []ColumnWidths
+	// FitToPage scales the widths of the contents of all columns down alike to fit the printable
+	// width of the page, rather than leave the narrow columns as they are.
+	FitToPage bool
 	// MaxColWidth float64 list that has the Maximum Column Width of each column based on data in the table.
 	MaxColWidth []float64
+	// HeaderCells replaces the Headers when set, with one or more header rows of cells that may
+	// span columns and rows, example a "Q1" cell spanning the columns of its three months.
+	HeaderCells [][]TableCell
+	// Cells replaces the Rows when set, with rows of cells that may span columns and rows.
+	// A cell spanning rows takes its columns in the rows below, which leave them out.
+	Cells [][]TableCell
+	// ColumnStyles contains the models.Styles of the data cells of each column, by index, in place
+	// of the RowStyle and AlternatingRowStyle. A style without a Name is left out.
+	ColumnStyles []models.Styles
+	// Conditions are applied to each data cell in turn, the models.Styles returned, if it has a
+	// Name, replacing the style of the cell. The last condition returning a style wins.
+	Conditions []TableCondition
+	// Footers is a footer row below the data rows in the alignment**content format. A column with
+	// a Totals calculation and an empty footer shows the result.
+	Footers []string
+	// FooterCells replaces the Footers when set, with one or more footer rows of cells that may
+	// span columns and rows. The Totals are not written in them.
+	FooterCells [][]TableCell
+	// FooterStyle contains the models.Styles of the footer and subtotal rows, the HeaderStyle if
+	// it has no Name.
+	FooterStyle models.Styles
+	// Totals is the calculation of each column, by index, written in the footer row, and in the
+	// subtotal row of each group. The values of a typed column of the ColumnFormats are
+	// calculated and written in its format. Numbers of other columns are read from the cell text,
+	// ignoring "," thousands separators and currency symbols, and "(12.50)" is negative.
+	Totals []models.Aggregates
+	// HasGroups denotes if the data rows are grouped by the text of their GroupBy column. The
+	// groups are in the order they first appear, each below a group header row and, with Totals,
+	// above a subtotal row. Rows joined by a cell spanning them are not kept together.
+	HasGroups bool
+	// GroupBy is the index of the column the data rows are grouped by.
+	GroupBy int
+	// GroupStyle contains the models.Styles of the group header rows, the HeaderStyle if it has
+	// no Name.
+	GroupStyle models.Styles
+	// SubtotalLabel is written in the first column without a Totals calculation of each subtotal
+	// row. Empty is "Subtotal".
+	SubtotalLabel string
+	// Padding is the space between the borders and the text of every cell. A Padding left as
+	// is, all 0, is 3 pts left and right.
+	Padding models.Margins
+	// VerticalAlign is the "T" top, "M" middle, or "B" bottom alignment of the text of every cell
+	// in the rows it spans. Empty is "T".
+	VerticalAlign string
+	// Borders is the set of lines drawn around the cells. Lines are drawn with the width and
+	// color of the side of the Border of the style of each cell, 1 pt wide when the side has
+	// no width.
+	Borders models.TableBorders
+	// ContinuedCaption is written above the header row repeated when the table continues on the
+	// next page, example "Expenses (continued)". Empty writes no caption.
+	ContinuedCaption string
 }
 
+// TableCondition is a conditional formatting rule of a table. It is given the row and column
+// index of a data cell, counted from 0 after the header without the group header and subtotal
+// rows of Tables.HasGroups, and its value. The value of a cell of
+// a typed column of Tables.ColumnFormats is a float64 number or a time.Time date, and of any
+// other cell its Value or else its Text. A models.Styles without a Name leaves the style of the
+// cell unchanged. As the value may be of any type check it before use.
+//
+// Example of negative numbers in red, in a typed column or written as text:
+//
+//	func(row, col int, value interface{}) models.Styles {
+//		switch v := value.(type) {
+//		case float64:
+//			if v < 0 {
+//				return negative
+//			}
+//		case string:
+//			if strings.HasPrefix(v, "-") {
+//				return negative
+//			}
+//		}
+//		return models.Styles{}
+//	}
+type TableCondition func(row, col int, value interface{}) models.Styles
+
 // BreakTableAlignment Tables function for spliting the alignment from the cell text.
 // C, L, R - Center, Left, Right is alignment of the cell contents.
 // Alignment must precede the cell contents followed by "**"
 // Example of this is "C**Cell text goes here". This means the cell text will be Centered.
+// Only the first "**" is taken as the separator, the cell text may contain more.
 func BreakTableAlignment(str string) (string, string) {
-	strs := strings.Split(str, "**")
+	strs := strings.SplitN(str, "**", 2)
 	if len(strs) < 2 {
 		strs = []string{"L", str}
 	}
 	return strings.ToUpper(strs[0]), strs[1]
 }
 
-// TableColumnWidth will determine the width of each column at the max width of the contents
-// plus 6 pts of padding.
+// TableColumnWidth will determine the width of each column at the max width of the contents,
+// in the style of each cell, plus the Tables.Padding. A cell spanning columns wider than the
+// columns it spans widens each of them evenly. When the columns are wider than the printable
+// width of the page, the columns wider than an even share keep their proportions in the width
+// left by the others, or with Tables.FitToPage all columns are scaled down alike. The
+// Tables.ColumnWidths then replace the widths of their columns.
 // This should NOT be used directly but is provided for context. Use AddTable() instead.
 func (s *SimPDF) TableColumnWidth(table Tables) []float64 {
-	iCols := make([]float64, len(table.Headers))
-	s.SetStyle(table.HeaderStyle, true)
-	for i := range iCols {
-		iCols[i] = math.Round(s.StringWidth(table.Headers[i])) + 6
-	}
-	s.SetStyle(table.RowStyle, true)
-	for _, str := range table.Rows {
-		for ix, j := range iCols {
-			vW := math.Round(s.StringWidth(str[ix])) + 6
-			if vW > j {
-				iCols[ix] = vW
+	return s.tableColumnWidths(table, s.printableWidth())
+}
+
+// tableColumnWidths returns the widths of the columns of the table as TableColumnWidth() does,
+// in width rather than the printable width of the page.
+func (s *SimPDF) tableColumnWidths(table Tables, width float64) []float64 {
+	iCols := make([]float64, table.columnCount())
+	var spans []placedCell
+	var spanWidths []float64
+	pad := table.padding()
+	measure := func(rows [][]TableCell, style func(placedCell) models.Styles) {
+		cells, _ := placeCells(rows)
+		for _, cell := range cells {
+			s.SetStyle(style(cell), true)
+			w := math.Round(s.StringWidth(cell.Text))
+			if cell.Image.FilePath != "" {
+				w = math.Max(w, s.cellImage(cell.Image, width).Width)
+			}
+			if cell.Table != nil {
+				w = math.Max(w, spanWidth(s.tableColumnWidths(*cell.Table, width), 0, cell.Table.columnCount()))
+			}
+			w += pad.Left + pad.Right
+			if cell.ColSpan > 1 {
+				spans = append(spans, cell)
+				spanWidths = append(spanWidths, w)
+			} else if w > iCols[cell.col] {
+				iCols[cell.col] = w
+			}
+		}
+	}
+	measure(table.headerRows(), table.headerCellStyle)
+	measure(table.bodyRows(), table.bodyCellStyle)
+	measure(table.footerRows(), table.footerCellStyle)
+	for i, cell := range spans {
+		if w := spanWidth(iCols, cell.col, cell.ColSpan); spanWidths[i] > w {
+			extra := (spanWidths[i] - w) / float64(cell.ColSpan)
+			for c := cell.col; c < cell.col+cell.ColSpan; c++ {
+				iCols[c] += extra
+			}
+		}
+	}
+	return resolveColumns(iCols, table.ColumnWidths, table.FitToPage, width)
+}
+
+// printableWidth returns the width of the page between the current left and right margins.
+func (s *SimPDF) printableWidth() float64 {
+	left, _, right, _ := s.PDF.GetMargins()
+	return s.Width() - left - right
+}
+
+// fitColumns returns the widths shrunk to fit in width. Columns narrower than an even share of
+// the width left keep their width, the rest share what remains in proportion to their width.
+func fitColumns(widths []float64, width float64) []float64 {
+	total := 0.0
+	for _, w := range widths {
+		total += w
+	}
+	if total <= width {
+		return widths
+	}
+	out := make([]float64, len(widths))
+	fixed := make([]bool, len(widths))
+	remaining, count := width, len(widths)
+	for changed := true; changed && count > 0; {
+		changed = false
+		share := remaining / float64(count)
+		for i, w := range widths {
+			if !fixed[i] && w <= share {
+				out[i], fixed[i] = w, true
+				remaining -= w
+				count--
+				changed = true
 			}
 		}
 	}
-	return iCols
+	wide := 0.0
+	for i, w := range widths {
+		if !fixed[i] {
+			wide += w
+		}
+	}
+	for i, w := range widths {
+		if !fixed[i] {
+			out[i] = math.Floor(remaining * w / wide)
+		}
+	}
+	return out
+}
+
+// columnWidths returns the width of each column, all being fixWidth if it is not 0.
+func (t *Tables) columnWidths(fixWidth float64) []float64 {
+	widths := make([]float64, t.columnCount())
+	for i := range widths {
+		if fixWidth == 0 && i < len(t.MaxColWidth) {
+			widths[i] = t.MaxColWidth[i]
+		} else {
+			widths[i] = fixWidth
+		}
+	}
+	return widths
+}
+
+// columnCount returns the number of columns of the table, the most of the Headers, the Footers,
+// and the columns taken by the header, data, and footer cells.
+func (t *Tables) columnCount() int {
+	count := len(t.Headers)
+	if len(t.Footers) > count {
+		count = len(t.Footers)
+	}
+	for _, rows := range [][][]TableCell{t.headerRows(), t.dataRows(), t.FooterCells} {
+		if _, cols := placeCells(rows); cols > count {
+			count = cols
+		}
+	}
+	return count
+}
+
+// headerRows returns the header rows of the table, the HeaderCells or else the Headers. There
+// are none when the Headers are not to be displayed.
+func (t *Tables) headerRows() [][]TableCell {
+	if t.HeaderCells != nil {
+		return t.HeaderCells
+	}
+	if len(t.Headers) == 0 || t.CheckNullHeaders() {
+		return nil
+	}
+	return tableCells([][]string{t.Headers})
+}
+
+// dataRows returns the data rows of the table, the Cells or else the Rows, with the values of
+// the ColumnFormats.
+func (t *Tables) dataRows() [][]TableCell {
+	if t.Cells != nil {
+		return t.typedRows(t.Cells)
+	}
+	return t.typedRows(tableCells(t.Rows))
+}
+
+// padding returns the Padding of the cells, 3 pts left and right if it is left as is.
+func (t *Tables) padding() models.Margins {
+	if t.Padding == (models.Margins{}) {
+		return models.Margins{Left: 3, Right: 3}
+	}
+	return t.Padding
+}
+
+// rowStyle returns the style of the data row at index, alternating if the table does.
+func (t *Tables) rowStyle(index int) models.Styles {
+	if index%2 == 1 && t.HasAlternating {
+		return t.AlternatingRowStyle
+	}
+	return t.RowStyle
+}
+
+// headerCellStyle returns the style of a header cell, its own Style or else the HeaderStyle.
+func (t *Tables) headerCellStyle(cell placedCell) models.Styles {
+	if cell.Style.Name != "" {
+		return cell.Style
+	}
+	return t.HeaderStyle
+}
+
+// bodyCellStyle returns the style of a data cell. Its own Style comes first, then the
+// Conditions, the ColumnStyles, and the style of its row. A cell of a group header or subtotal
+// row without a Style is in the RowStyle.
+func (t *Tables) bodyCellStyle(cell placedCell) models.Styles {
+	if cell.Style.Name != "" {
+		return cell.Style
+	}
+	if cell.summary {
+		return t.RowStyle
+	}
+	style := t.rowStyle(cell.dataRow)
+	if cell.col < len(t.ColumnStyles) && t.ColumnStyles[cell.col].Name != "" {
+		style = t.ColumnStyles[cell.col]
+	}
+	for _, condition := range t.Conditions {
+		value := cell.Value
+		if value == nil {
+			value = cell.Text
+		}
+		if sty := condition(cell.dataRow, cell.col, value); sty.Name != "" {
+			style = sty
+		}
+	}
+	return style
 }
 
 // CheckNullHeaders looks at each Tables.Header string value to see if it is a length of less than 1.
@@ -76,61 +334,356 @@ func (t *Tables) CheckNullHeaders() bool {
 	return aF
 }
 
-// AddTableHeader Adds the table header row to the PDF document. If fixWidth is not 0 then
-// all cells will be set to the fixed width of the fixWidth value.
+// AddTableHeader Adds the table header rows to the PDF document. If fixWidth is not 0 then
+// all cells will be set to the fixed width of the fixWidth value. The header starts a new page
+// rather than be left at the bottom of a page without the first rows.
 // This should NOT be used directly but is provided for context. Use AddTable() instead.
 func (s *SimPDF) AddTableHeader(table Tables, fixWidth float64) {
-	if !table.CheckNullHeaders() {
-		s.SetStyle(table.HeaderStyle, false)
-		for i, r := range table.Headers {
-			b := fmt.Sprintf("%g", table.HeaderStyle.Border.Width.Top)
-			var w float64
-			if fixWidth == 0 {
-				w = table.MaxColWidth[i]
-			} else {
-				w = fixWidth
-			}
-			align, str := BreakTableAlignment(r)
-			s.PDF.CellFormat(w, table.HeaderStyle.LineSize, str, b, 0, align, true, 0, "")
-		}
-		s.NewLine(table.HeaderStyle.LineSize)
+	widths := table.columnWidths(fixWidth)
+	header := s.layoutTable(table, headerPart, widths)
+	if len(header.heights) == 0 {
+		return
+	}
+	height := header.height(0, len(header.heights))
+	body := s.layoutTable(table, bodyPart, widths)
+	if blocks := body.blocks(); len(blocks) > 0 {
+		height += body.height(blocks[0][0], blocks[0][1])
+	}
+	if s.PDF.GetY()+height > s.pageBottom() {
+		s.Break()
 	}
+	s.drawTable(header, widths, 0, len(header.heights), s.leftMargin())
 }
 
-// AddTableRows Adds the table rows to the PDF document. If fixWidth is not 0 then
-// all cells will be set to the fixed width of the fixWidth value.
+// AddTableRows Adds the table rows, and the footer rows, to the PDF document. If fixWidth is
+// not 0 then all cells will be set to the fixed width of the fixWidth value. A row, or the rows
+// joined by a cell spanning them, is never split between pages. Rows that do not fit at the
+// bottom of the page start a new page with the Tables.ContinuedCaption, if any, and the header
+// repeated.
 // This should NOT be used directly but is provided for context. Use AddTable() instead.
 func (s *SimPDF) AddTableRows(table Tables, fixWidth float64) {
-	for ir, r := range table.Rows {
-		b := fmt.Sprintf("%g", table.RowStyle.Border.Width.Top)
-		if ir%2 == 1 {
-			if table.HasAlternating {
-				s.SetStyle(table.AlternatingRowStyle, false)
+	widths := table.columnWidths(fixWidth)
+	body := s.layoutTable(table, bodyPart, widths)
+	var header *tableLayout
+	draw := func(layout tableLayout, from, to int) {
+		if s.PDF.GetY()+layout.height(from, to) > s.pageBottom() {
+			if header == nil {
+				hl := s.layoutTable(table, headerPart, widths)
+				header = &hl
 			}
-		} else {
-			s.SetStyle(table.RowStyle, false)
-		}
-		for ix, n := range r {
-			var w float64
-			if fixWidth == 0 {
-				w = table.MaxColWidth[ix]
-			} else {
-				w = fixWidth
+			s.continueTable(table, *header, widths)
+		}
+		s.drawTable(layout, widths, from, to, s.leftMargin())
+	}
+	for _, block := range body.blocks() {
+		draw(body, block[0], block[1])
+	}
+	if footer := s.layoutTable(table, footerPart, widths); len(footer.heights) > 0 {
+		draw(footer, 0, len(footer.heights))
+	}
+	if sty, ok := s.style("Normal"); ok {
+		s.SetStyle(sty, false)
+	}
+}
+
+// continueTable starts a new page for the rest of the table with the Tables.ContinuedCaption,
+// if any, and the header laid out.
+func (s *SimPDF) continueTable(table Tables, header tableLayout, widths []float64) {
+	s.Break()
+	if table.ContinuedCaption != "" {
+		if style, ok := s.style("Normal"); ok {
+			s.Font(style)
+			s.PDF.CellFormat(s.printableWidth(), style.LineSize, table.ContinuedCaption, "", 1, "L", false, 0, "")
+		}
+	}
+	s.drawTable(header, widths, 0, len(header.heights), s.leftMargin())
+}
+
+// tableParts is a part of a table, the header, body, or footer rows.
+type tableParts int
+
+const (
+	headerPart tableParts = iota
+	bodyPart
+	footerPart
+)
+
+// tableLayout is the rows of a part of a table laid out to be drawn.
+type tableLayout struct {
+	cells   []layoutCell
+	heights []float64
+	part    tableParts
+	// top and bottom are true when the first and last rows are the edges of the table.
+	top, bottom bool
+	borders     models.TableBorders
+	padding     models.Margins
+}
+
+// layoutCell is a cell of a table laid out to be drawn.
+type layoutCell struct {
+	placedCell
+	style  models.Styles
+	lines  [][]byte
+	valign string
+	image  Images
+	nested *nestedTable
+	// inset is the space right of right aligned text, lining up its decimal point with that of
+	// negative numbers in parentheses.
+	inset float64
+}
+
+// height returns the height of the cell content, the text, image, and nested table, without the
+// padding.
+func (c layoutCell) height() float64 {
+	h := float64(len(c.lines))*c.style.LineSize + c.image.Height
+	if c.nested != nil {
+		for _, part := range c.nested.parts {
+			h += part.height(0, len(part.heights))
+		}
+	}
+	return h
+}
+
+// layoutTable lays out the rows of the part of the table in the style of each cell, every row
+// being at least a line of the style of the row. Text wider than its cell wraps, images are
+// shrunk to fit, and every row is as tall as its tallest cell. A cell spanning rows taller than them makes the last of its
+// rows taller.
+func (s *SimPDF) layoutTable(table Tables, part tableParts, widths []float64) tableLayout {
+	var rows [][]TableCell
+	rowStyle, cellStyle := table.rowStyle, table.bodyCellStyle
+	switch part {
+	case headerPart:
+		rows, cellStyle = table.headerRows(), table.headerCellStyle
+		rowStyle = func(int) models.Styles { return table.HeaderStyle }
+	case bodyPart:
+		rows = table.bodyRows()
+		rowStyle = func(r int) models.Styles { return table.bodyRowStyle(rows[r]) }
+	case footerPart:
+		rows, cellStyle = table.footerRows(), table.footerCellStyle
+		rowStyle = func(int) models.Styles { return table.footerStyle() }
+	}
+	layout := tableLayout{
+		heights: make([]float64, len(rows)),
+		part:    part,
+		top:     part == headerPart || (part == bodyPart && len(table.headerRows()) == 0),
+		bottom:  part == footerPart || (part == bodyPart && len(table.footerRows()) == 0),
+		borders: table.Borders,
+		padding: table.padding(),
+	}
+	pad := layout.padding
+	for r := range rows {
+		layout.heights[r] = rowStyle(r).LineSize + pad.Top + pad.Bottom
+	}
+	cm := s.PDF.GetCellMargin()
+	s.PDF.SetCellMargin(0)
+	placed, _ := placeCells(rows)
+	for _, cell := range placed {
+		lc := layoutCell{placedCell: cell, style: cellStyle(cell), valign: strings.ToUpper(table.VerticalAlign)}
+		if cell.VerticalAlign != "" {
+			lc.valign = strings.ToUpper(cell.VerticalAlign)
+		}
+		inner := spanWidth(widths, cell.col, cell.ColSpan) - pad.Left - pad.Right
+		s.SetStyle(lc.style, true)
+		if f, ok := table.columnFormat(cell.col); ok && part != headerPart && f.Parentheses && f.Type.Numeric() &&
+			cell.Align == "R" && cell.ColSpan == 1 && !strings.HasSuffix(cell.Text, ")") {
+			lc.inset = s.StringWidth(")")
+		}
+		if cell.Text != "" {
+			lc.lines = s.PDF.SplitLines([]byte(cell.Text), inner-lc.inset)
+		}
+		if cell.Image.FilePath != "" {
+			lc.image = s.cellImage(cell.Image, inner)
+		}
+		if cell.Table != nil {
+			lc.nested = s.layoutNestedTable(*cell.Table, inner)
+		}
+		layout.cells = append(layout.cells, lc)
+		if h := lc.height() + pad.Top + pad.Bottom; cell.RowSpan == 1 && h > layout.heights[cell.row] {
+			layout.heights[cell.row] = h
+		}
+	}
+	s.PDF.SetCellMargin(cm)
+	for _, cell := range layout.cells {
+		last := cell.row + cell.RowSpan
+		if h := cell.height() + pad.Top + pad.Bottom; cell.RowSpan > 1 && h > layout.height(cell.row, last) {
+			layout.heights[last-1] += h - layout.height(cell.row, last)
+		}
+	}
+	return layout
+}
+
+// height returns the height of the rows from up to, not including, to.
+func (l tableLayout) height(from, to int) float64 {
+	h := 0.0
+	for _, rh := range l.heights[from:to] {
+		h += rh
+	}
+	return h
+}
+
+// blocks returns the rows of the layout as the start and end of the blocks of rows that must
+// stay together, a row alone or the rows joined by cells spanning them.
+func (l tableLayout) blocks() [][2]int {
+	ends := make([]int, len(l.heights))
+	for r := range ends {
+		ends[r] = r + 1
+	}
+	for _, cell := range l.cells {
+		if end := cell.row + cell.RowSpan; end > ends[cell.row] {
+			ends[cell.row] = end
+		}
+	}
+	var blocks [][2]int
+	for start := 0; start < len(ends); {
+		end := ends[start]
+		for r := start; r < end; r++ {
+			if ends[r] > end {
+				end = ends[r]
 			}
-			align, str := BreakTableAlignment(n)
-			s.PDF.CellFormat(w, table.RowStyle.LineSize, str, b, 0, align, true, 0, "")
 		}
-		s.NewLine(table.RowStyle.LineSize)
+		blocks = append(blocks, [2]int{start, end})
+		start = end
 	}
-	sty, err := s.StyleName("Normal")
-	internal.IfError("AddTableRows secure", err, false)
-	s.SetStyle(sty, false)
+	return blocks
+}
+
+// drawTable draws the rows of the layout from up to, not including, to at left and the current
+// Y position and moves below them. Each cell is drawn across the columns and rows it spans, its
+// background and text first and then, so no background covers them, the borders.
+func (s *SimPDF) drawTable(layout tableLayout, widths []float64, from, to int, left float64) {
+	y := s.PDF.GetY()
+	pad := layout.padding
+	cm := s.PDF.GetCellMargin()
+	s.PDF.SetCellMargin(0)
+	bounds := func(cell layoutCell) (x, top, w, h float64) {
+		return left + spanWidth(widths, 0, cell.col), y + layout.height(from, cell.row),
+			spanWidth(widths, cell.col, cell.ColSpan), layout.height(cell.row, cell.row+cell.RowSpan)
+	}
+	var cells []layoutCell
+	for _, cell := range layout.cells {
+		if cell.row >= from && cell.row < to {
+			cells = append(cells, cell)
+		}
+	}
+	for _, cell := range cells {
+		x, top, w, h := bounds(cell)
+		s.SetStyle(cell.style, false)
+		s.PDF.Rect(x, top, w, h, "F")
+		align := cell.Align
+		if align == "" {
+			align = "L"
+		}
+		offset := pad.Top
+		switch cell.valign {
+		case "M":
+			offset += (h - pad.Top - pad.Bottom - cell.height()) / 2
+		case "B":
+			offset += h - pad.Top - pad.Bottom - cell.height()
+		}
+		inner := w - pad.Left - pad.Right
+		for il, line := range cell.lines {
+			s.PDF.SetXY(x+pad.Left, top+offset+(float64(il)*cell.style.LineSize))
+			s.PDF.CellFormat(inner-cell.inset, cell.style.LineSize, string(line), "", 0, align, false, 0, "")
+		}
+		offset += float64(len(cell.lines)) * cell.style.LineSize
+		if cell.image.FilePath != "" {
+			s.AddImageXY(cell.image, alignX(x+pad.Left, inner, cell.image.Width, align), top+offset)
+			offset += cell.image.Height
+		}
+		if cell.nested != nil {
+			nx := alignX(x+pad.Left, inner, spanWidth(cell.nested.widths, 0, len(cell.nested.widths)), align)
+			s.PDF.SetXY(nx, top+offset)
+			for _, part := range cell.nested.parts {
+				s.drawTable(part, cell.nested.widths, 0, len(part.heights), nx)
+			}
+		}
+	}
+	for _, cell := range cells {
+		x, top, w, h := bounds(cell)
+		sides := layout.sides(cell, len(widths))
+		for _, side := range []string{"L", "T", "R", "B"} {
+			if !strings.Contains(sides, side) {
+				continue
+			}
+			width, color := cell.style.Border.Side(side)
+			if width <= 0 {
+				width = 1
+			}
+			s.PDF.SetLineWidth(width)
+			s.PDF.SetDrawColor(int(color.Red), int(color.Green), int(color.Blue))
+			switch side {
+			case "L":
+				s.PDF.Line(x, top, x, top+h)
+			case "T":
+				s.PDF.Line(x, top, x+w, top)
+			case "R":
+				s.PDF.Line(x+w, top, x+w, top+h)
+			case "B":
+				s.PDF.Line(x, top+h, x+w, top+h)
+			}
+		}
+	}
+	s.PDF.SetCellMargin(cm)
+	s.PDF.SetXY(left, y+layout.height(from, to))
+}
+
+// sides returns the sides of the cell with a border drawn by the Tables.Borders, "L" left,
+// "T" top, "R" right, and "B" bottom, of a table of cols columns.
+func (l tableLayout) sides(cell layoutCell, cols int) string {
+	last := cell.row+cell.RowSpan == len(l.heights)
+	switch l.borders {
+	case models.GridBorders:
+		return "LTRB"
+	case models.HorizontalBorders:
+		return "TB"
+	case models.OuterBorders:
+		sides := ""
+		if cell.col == 0 {
+			sides += "L"
+		}
+		if cell.row == 0 && l.top {
+			sides += "T"
+		}
+		if cell.col+cell.ColSpan >= cols {
+			sides += "R"
+		}
+		if last && l.bottom {
+			sides += "B"
+		}
+		return sides
+	case models.HeaderUnderline:
+		if l.part == headerPart && last {
+			return "B"
+		}
+		return ""
+	case models.NoBorders:
+		return ""
+	}
+	sides := ""
+	for _, side := range []string{"L", "T", "R", "B"} {
+		if width, _ := cell.style.Border.Side(side); width > 0 {
+			sides += side
+		}
+	}
+	return sides
+}
+
+// spanWidth returns the width of count columns from the column at index.
+func spanWidth(widths []float64, index, count int) float64 {
+	w := 0.0
+	for i := index; i < index+count && i < len(widths); i++ {
+		w += widths[i]
+	}
+	return w
 }
 
 // AddTable Simply adds the table to the PDF document. This is the main function for adding a
 // table to the document. If fixWidth is not 0 then all cells will be set to the fixed width of
 // the fixWidth value. If it is 0 then the width will be dependent on the cell contents.
 func (s *SimPDF) AddTable(table Tables, altRowColor models.Styles, fixedWidth float64) {
+	if s.Err() {
+		return
+	}
 	if altRowColor.Name != "" {
 		table.HasAlternating = true
 		table.AlternatingRowStyle = altRowColor
@@ -140,9 +693,7 @@ func (s *SimPDF) AddTable(table Tables, altRowColor models.Styles, fixedWidth fl
 	table.MaxColWidth = s.TableColumnWidth(table)
 	s.AddTableHeader(table, fixedWidth)
 	s.AddTableRows(table, fixedWidth)
-	style, err := s.StyleName("Normal")
-	internal.IfError("AddTable public", err, false)
-	s.fontReset(style)
+	s.fontReset(models.Styles{})
 	s.NewLine(0)
 }
 
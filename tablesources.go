@@ -0,0 +1,309 @@
+package simpdf
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/braddschick/simpdf/pkg/defaults"
+)
+
+// TableFromStructs returns a table of slice, a slice or array of structs or pointers to structs,
+// with a column for each exported field. The pdf struct tag sets the header of the column, its
+// alignment, and the fmt format of its values, example `pdf:"Amount,align=R,format=%.2f"`. A
+// field tagged `pdf:"-"` is left out. Without an align the alignment is inferred from the type of
+// the field, numbers are "R" right and bools "C" center aligned.
+// A slice of anything else returns an error wrapping ErrInvalidTableSource.
+func TableFromStructs(slice interface{}) (Tables, error) {
+	v := reflect.ValueOf(slice)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return Tables{}, fmt.Errorf("%w: %T is not a slice", ErrInvalidTableSource, slice)
+	}
+	typ := v.Type().Elem()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return Tables{}, fmt.Errorf("%w: %T is not a slice of structs", ErrInvalidTableSource, slice)
+	}
+	type column struct {
+		field  int
+		format string
+	}
+	var columns []column
+	var headers, aligns []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("pdf")
+		if field.PkgPath != "" || tag == "-" {
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		header, align, format := opts[0], typeAlign(field.Type), ""
+		if header == "" {
+			header = field.Name
+		}
+		for _, opt := range opts[1:] {
+			key, value := opt, ""
+			if eq := strings.Index(opt, "="); eq >= 0 {
+				key, value = opt[:eq], opt[eq+1:]
+			}
+			switch strings.TrimSpace(key) {
+			case "align":
+				align = strings.ToUpper(value)
+			case "format":
+				format = value
+			}
+		}
+		columns = append(columns, column{field: i, format: format})
+		headers = append(headers, header)
+		aligns = append(aligns, align)
+	}
+	rows := make([][]string, v.Len())
+	for r := range rows {
+		item := reflect.Indirect(v.Index(r))
+		rows[r] = make([]string, len(columns))
+		if !item.IsValid() {
+			continue
+		}
+		for c, col := range columns {
+			rows[r][c] = formatValue(item.Field(col.field).Interface(), col.format)
+		}
+	}
+	return newTable(headers, aligns, rows), nil
+}
+
+// TableFromMaps returns a table of rows with a column for each of the columns, the keys of the
+// maps. Without columns there is a column for every key, in sorted order. The alignment of a
+// column is inferred from its values, numbers are "R" right and bools "C" center aligned.
+func TableFromMaps(rows []map[string]interface{}, columns []string) Tables {
+	if len(columns) == 0 {
+		keys := make(map[string]bool)
+		for _, row := range rows {
+			for key := range row {
+				if !keys[key] {
+					keys[key] = true
+					columns = append(columns, key)
+				}
+			}
+		}
+		sort.Strings(columns)
+	}
+	values := make([][]interface{}, len(rows))
+	for r, row := range rows {
+		values[r] = make([]interface{}, len(columns))
+		for c, col := range columns {
+			values[r][c] = row[col]
+		}
+	}
+	return valuesTable(columns, values)
+}
+
+// TableFromCSV returns a table of the CSV read from r, the first record being the headers. The
+// alignment of a column is inferred from its values, a column of numbers is "R" right aligned.
+func TableFromCSV(r io.Reader) (Tables, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return Tables{}, fmt.Errorf("simpdf: reading csv: %w", err)
+	}
+	if len(records) == 0 {
+		return newTable(nil, nil, nil), nil
+	}
+	values := make([][]interface{}, len(records)-1)
+	for r, record := range records[1:] {
+		values[r] = make([]interface{}, len(record))
+		for c, field := range record {
+			values[r][c] = field
+		}
+	}
+	return valuesTable(records[0], values), nil
+}
+
+// TableFromSQLRows returns a table of the rows of a query, with a column for each column of the
+// result. The rows are read to the end but not closed. The alignment of a column is inferred
+// from its values, numbers are "R" right and bools "C" center aligned.
+func TableFromSQLRows(rows *sql.Rows) (Tables, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return Tables{}, fmt.Errorf("simpdf: reading sql columns: %w", err)
+	}
+	var values [][]interface{}
+	for rows.Next() {
+		row := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return Tables{}, fmt.Errorf("simpdf: reading sql rows: %w", err)
+		}
+		values = append(values, row)
+	}
+	if err := rows.Err(); err != nil {
+		return Tables{}, fmt.Errorf("simpdf: reading sql rows: %w", err)
+	}
+	return valuesTable(columns, values), nil
+}
+
+// valuesTable returns a table of the values of each row, the alignment of each column inferred
+// from its values.
+func valuesTable(headers []string, values [][]interface{}) Tables {
+	aligns := make([]string, len(headers))
+	for c := range aligns {
+		for _, row := range values {
+			if c >= len(row) || isEmptyValue(row[c]) {
+				continue
+			}
+			align := valueAlign(row[c])
+			if aligns[c] == "" {
+				aligns[c] = align
+			} else if aligns[c] != align {
+				aligns[c] = "L"
+				break
+			}
+		}
+	}
+	rows := make([][]string, len(values))
+	for r, row := range values {
+		rows[r] = make([]string, len(row))
+		for c, value := range row {
+			rows[r][c] = formatValue(value, "")
+		}
+	}
+	return newTable(headers, aligns, rows)
+}
+
+// newTable returns a table of the headers and rows, each column in its alignment, in the
+// "Table" style of defaults.Basic_Table with a bold header row.
+func newTable(headers, aligns []string, rows [][]string) Tables {
+	header := defaults.Basic_Table
+	header.TextVariant.Bold = true
+	table := Tables{
+		Headers:     make([]string, len(headers)),
+		HeaderStyle: header,
+		RowStyle:    defaults.Basic_Table,
+		Rows:        make([][]string, len(rows)),
+	}
+	align := func(c int) string {
+		if c < len(aligns) && aligns[c] != "" {
+			return aligns[c]
+		}
+		return "L"
+	}
+	for c, h := range headers {
+		table.Headers[c] = align(c) + "**" + h
+	}
+	for r, row := range rows {
+		table.Rows[r] = make([]string, len(row))
+		for c, text := range row {
+			table.Rows[r][c] = align(c) + "**" + text
+		}
+	}
+	return table
+}
+
+// typeAlign returns the alignment of values of the type, "R" for numbers, "C" for bools, and
+// "L" for all else.
+func typeAlign(typ reflect.Type) string {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "R"
+	case reflect.Bool:
+		return "C"
+	}
+	return "L"
+}
+
+// valueAlign returns the alignment of the value, text of a number being aligned as a number.
+func valueAlign(value interface{}) string {
+	switch v := driverValue(value).(type) {
+	case string:
+		return textAlign(v)
+	case []byte:
+		return textAlign(string(v))
+	case nil:
+		return "L"
+	default:
+		return typeAlign(reflect.TypeOf(v))
+	}
+}
+
+// textAlign returns "R" for the text of a number, with or without thousands separators, and
+// "L" for all else.
+func textAlign(text string) string {
+	if _, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(text), ",", "", -1), 64); err == nil {
+		return "R"
+	}
+	return "L"
+}
+
+// isEmptyValue returns true if the value is written as an empty cell.
+func isEmptyValue(value interface{}) bool {
+	return strings.TrimSpace(formatValue(value, "")) == ""
+}
+
+// formatValue returns the text of the value in the fmt format, or as is without one. A
+// time.Time without a format is its date, and time of day if it has one. A nil value, nil
+// pointer, or zero time.Time is empty.
+func formatValue(value interface{}, format string) string {
+	value = driverValue(value)
+	if value == nil {
+		return ""
+	}
+	switch v := value.(type) {
+	case []byte:
+		value = string(v)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		if format == "" {
+			if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 {
+				return v.Format("2006-01-02")
+			}
+			return v.Format("2006-01-02 15:04")
+		}
+	}
+	if format != "" {
+		return fmt.Sprintf(format, value)
+	}
+	return fmt.Sprint(value)
+}
+
+// driverValue returns the value a pointer points to, or the value of a driver.Valuer such as
+// sql.NullString, nil if there is none.
+func driverValue(value interface{}) interface{} {
+	if valuer, ok := value.(driver.Valuer); ok {
+		if v := reflect.ValueOf(valuer); v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil
+		}
+		v, err := valuer.Value()
+		if err != nil {
+			return nil
+		}
+		value = v
+	}
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+		value = v.Interface()
+	}
+	return value
+}
@@ -0,0 +1,237 @@
+package simpdf
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/braddschick/simpdf/pkg/models"
+)
+
+// bodyRows returns the data rows of the table, in their groups with the group header and
+// subtotal rows when it HasGroups. Each data cell is given the index of its data row.
+func (t *Tables) bodyRows() [][]TableCell {
+	rows := t.dataRows()
+	if !t.HasGroups {
+		return numberRows(rows)
+	}
+	var keys []string
+	groups := make(map[string][][]TableCell)
+	for _, row := range rows {
+		key := ""
+		cells, _ := placeCells([][]TableCell{row})
+		for _, cell := range cells {
+			if cell.col == t.GroupBy {
+				key = cell.Text
+			}
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], row)
+	}
+	label := t.SubtotalLabel
+	if label == "" {
+		label = "Subtotal"
+	}
+	cols, groupStyle := t.columnCount(), t.groupStyle()
+	var out [][]TableCell
+	for _, key := range keys {
+		out = append(out, []TableCell{{Text: key, ColSpan: cols, Style: groupStyle, summary: true}})
+		out = append(out, groups[key]...)
+		if len(t.Totals) > 0 {
+			labels := make([]TableCell, cols)
+			for i := range labels {
+				if i >= len(t.Totals) || t.Totals[i] == models.NoAggregate {
+					labels[i].Text = label
+					break
+				}
+			}
+			subtotal := t.totalsRow(groups[key], labels, t.footerStyle())
+			for i := range subtotal {
+				subtotal[i].summary = true
+			}
+			out = append(out, subtotal)
+		}
+	}
+	return numberRows(out)
+}
+
+// numberRows returns a copy of the rows with the cells of each data row given the index of the
+// row among the data rows.
+func numberRows(rows [][]TableCell) [][]TableCell {
+	out := make([][]TableCell, len(rows))
+	index := 0
+	for r, row := range rows {
+		out[r] = make([]TableCell, len(row))
+		copy(out[r], row)
+		if len(row) > 0 && row[0].summary {
+			continue
+		}
+		for i := range out[r] {
+			out[r][i].dataRow = index
+		}
+		index++
+	}
+	return out
+}
+
+// bodyRowStyle returns the style of a body row, the style of a group header or subtotal row or
+// else that of the data row.
+func (t *Tables) bodyRowStyle(row []TableCell) models.Styles {
+	if len(row) == 0 {
+		return t.RowStyle
+	}
+	if row[0].summary {
+		if row[0].Style.Name != "" {
+			return row[0].Style
+		}
+		return t.RowStyle
+	}
+	return t.rowStyle(row[0].dataRow)
+}
+
+// footerRows returns the footer rows of the table, the FooterCells or else the Footers with the
+// Totals of the data rows.
+func (t *Tables) footerRows() [][]TableCell {
+	if t.FooterCells != nil {
+		return t.FooterCells
+	}
+	if len(t.Footers) == 0 && len(t.Totals) == 0 {
+		return nil
+	}
+	return [][]TableCell{t.totalsRow(t.dataRows(), tableCells([][]string{t.Footers})[0], models.Styles{})}
+}
+
+// totalsRow returns a row of the labels, with the Totals of the rows in the columns without a
+// label. The cells of the row are in the style given if it has a Name.
+func (t *Tables) totalsRow(rows [][]TableCell, labels []TableCell, style models.Styles) []TableCell {
+	row := make([]TableCell, t.columnCount())
+	copy(row, labels)
+	placed, _ := placeCells(rows)
+	for col := range row {
+		if col >= len(t.Totals) || t.Totals[col] == models.NoAggregate || row[col].Text != "" {
+			continue
+		}
+		var texts []string
+		var values []float64
+		for _, cell := range placed {
+			if cell.col == col && cell.ColSpan == 1 {
+				texts = append(texts, cell.Text)
+				if v, ok := cell.Value.(float64); ok {
+					values = append(values, v)
+				}
+			}
+		}
+		row[col] = TableCell{Text: aggregate(t.Totals[col], texts), Align: "R"}
+		if f, ok := t.columnFormat(col); ok && f.Type.Numeric() && t.Totals[col] != models.Count && len(values) > 0 {
+			if f.Type == models.IntColumn && t.Totals[col] == models.Average {
+				f.Type, f.Decimals = models.DecimalColumn, 2
+			}
+			value := calculate(t.Totals[col], values)
+			row[col] = TableCell{Text: f.write(value), Value: value, Align: "R"}
+		}
+	}
+	for i := range row {
+		row[i].Style = style
+	}
+	return row
+}
+
+// footerStyle returns the style of the footer and subtotal rows.
+func (t *Tables) footerStyle() models.Styles {
+	if t.FooterStyle.Name != "" {
+		return t.FooterStyle
+	}
+	return t.HeaderStyle
+}
+
+// groupStyle returns the style of the group header rows.
+func (t *Tables) groupStyle() models.Styles {
+	if t.GroupStyle.Name != "" {
+		return t.GroupStyle
+	}
+	return t.HeaderStyle
+}
+
+// footerCellStyle returns the style of a footer cell, its own Style or else the footer style.
+func (t *Tables) footerCellStyle(cell placedCell) models.Styles {
+	if cell.Style.Name != "" {
+		return cell.Style
+	}
+	return t.footerStyle()
+}
+
+// aggregate returns the calculation of the texts, written to the most decimals of the numbers.
+// An Average has at least 2 decimals, and a calculation without numbers is empty.
+func aggregate(agg models.Aggregates, texts []string) string {
+	var values []float64
+	count, decimals := 0, 0
+	for _, text := range texts {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		count++
+		if v, d, ok := parseNumber(text); ok {
+			values = append(values, v)
+			if d > decimals {
+				decimals = d
+			}
+		}
+	}
+	if agg == models.Count {
+		return strconv.Itoa(count)
+	}
+	if len(values) == 0 || agg == models.NoAggregate {
+		return ""
+	}
+	if agg == models.Average && decimals < 2 {
+		decimals = 2
+	}
+	return strconv.FormatFloat(calculate(agg, values), 'f', decimals, 64)
+}
+
+// calculate returns the sum, average, minimum, or maximum of the values, at least one.
+func calculate(agg models.Aggregates, values []float64) float64 {
+	result := values[0]
+	switch agg {
+	case models.Sum, models.Average:
+		for _, v := range values[1:] {
+			result += v
+		}
+		if agg == models.Average {
+			result /= float64(len(values))
+		}
+	case models.Minimum:
+		for _, v := range values[1:] {
+			result = math.Min(result, v)
+		}
+	case models.Maximum:
+		for _, v := range values[1:] {
+			result = math.Max(result, v)
+		}
+	}
+	return result
+}
+
+// parseNumber returns the number of the text and its decimals, ignoring "," thousands
+// separators, currency symbols, and spaces. "(12.50)" is negative. ok is false for text that
+// is not a number.
+func parseNumber(text string) (value float64, decimals int, ok bool) {
+	text = strings.NewReplacer(",", "", " ", "", "$", "", "€", "", "£", "", "¥", "").Replace(text)
+	negative := strings.HasPrefix(text, "(") && strings.HasSuffix(text, ")")
+	if negative {
+		text = text[1 : len(text)-1]
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if i := strings.Index(text, "."); i >= 0 {
+		decimals = len(text) - i - 1
+	}
+	if negative {
+		value = -value
+	}
+	return value, decimals, true
+}
@@ -0,0 +1,62 @@
+package simpdf
+
+import (
+	"testing"
+
+	"github.com/braddschick/simpdf/pkg/models"
+)
+
+// TestGroupedBodyCellStyle checks that the group header and subtotal rows of a grouped table
+// take no conditions or column styles, and that data rows alternate as if they were not there.
+func TestGroupedBodyCellStyle(t *testing.T) {
+	row, alt, column, hit := models.Styles{Name: "Row"}, models.Styles{Name: "Alt"}, models.Styles{Name: "Column"},
+		models.Styles{Name: "Hit"}
+	var calls []interface{}
+	table := Tables{
+		Rows: [][]string{
+			{"East", "1"}, {"West", "2"}, {"East", "3"}, {"West", "4"},
+		},
+		RowStyle:            row,
+		HasAlternating:      true,
+		AlternatingRowStyle: alt,
+		ColumnStyles:        []models.Styles{{}, column},
+		Conditions: []TableCondition{func(r, col int, value interface{}) models.Styles {
+			calls = append(calls, value)
+			if col == 0 && r == 3 {
+				return hit
+			}
+			return models.Styles{}
+		}},
+		Totals:    []models.Aggregates{models.NoAggregate, models.Sum},
+		HasGroups: true,
+	}
+	rows := table.bodyRows()
+	placed, _ := placeCells(rows)
+	var got []string
+	for _, cell := range placed {
+		if cell.col == 0 {
+			got = append(got, table.bodyCellStyle(cell).Name)
+		} else {
+			table.bodyCellStyle(cell)
+		}
+	}
+	// East, East 1, East 3, Subtotal, West, West 2, West 4, Subtotal
+	want := []string{"Row", "Row", "Alt", "Row", "Row", "Row", "Hit", "Row"}
+	if len(got) != len(want) {
+		t.Fatalf("styles = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("styles = %v, want %v", got, want)
+			break
+		}
+	}
+	for _, value := range calls {
+		if value == "Subtotal" {
+			t.Errorf("condition given %v of a group header or subtotal row", value)
+		}
+	}
+	if len(calls) != 8 {
+		t.Errorf("condition called %d times, want 8 for the data cells", len(calls))
+	}
+}
@@ -0,0 +1,152 @@
+package simpdf
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ColumnWidths struct is the width of a column of a table. Only one of Points, Percent, or
+// Fraction is used, in that order, and without any the column is as wide as its contents.
+type ColumnWidths struct {
+	// Points is the width of the column in pts.
+	Points float64
+	// Percent is the width of the column in percent of the printable width of the page.
+	Percent float64
+	// Fraction is the weight of the column in the width left by the other columns, shared by
+	// all columns with a Fraction, example 2 for "2fr".
+	Fraction float64
+	// Min is the least width of the column in pts, 0 is no least width.
+	Min float64
+	// Max is the greatest width of the column in pts, 0 is no greatest width.
+	Max float64
+}
+
+// ParseColumnWidths returns the column widths of spec, a width for each column separated by
+// spaces. A width is "auto" for the width of the contents, pts as "120" or "120pt", a percent
+// as "25%", or a fraction as "2fr", example "120pt 2fr 1fr 1fr". A width that is none of these
+// returns an error wrapping ErrInvalidColumnWidth.
+func ParseColumnWidths(spec string) ([]ColumnWidths, error) {
+	var widths []ColumnWidths
+	for _, field := range strings.Fields(spec) {
+		var w ColumnWidths
+		if strings.ToLower(field) == "auto" {
+			widths = append(widths, w)
+			continue
+		}
+		unit := strings.TrimLeft(strings.ToLower(field), "0123456789.")
+		value, err := strconv.ParseFloat(field[:len(field)-len(unit)], 64)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidColumnWidth, field)
+		}
+		switch unit {
+		case "", "pt":
+			w.Points = value
+		case "%":
+			w.Percent = value
+		case "fr":
+			w.Fraction = value
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrInvalidColumnWidth, field)
+		}
+		widths = append(widths, w)
+	}
+	return widths, nil
+}
+
+// clamp returns w within the Min and Max of the column.
+func (c ColumnWidths) clamp(w float64) float64 {
+	if c.Max > 0 && w > c.Max {
+		w = c.Max
+	}
+	if w < c.Min {
+		w = c.Min
+	}
+	return w
+}
+
+// resolveColumns returns the widths of the columns in the width of the page. Columns of Points
+// or Percent take their width first. The columns as wide as their contents are fit in the width
+// left, scaled alike when fit is true, and the columns of a Fraction share what then remains.
+func resolveColumns(contents []float64, specs []ColumnWidths, fit bool, page float64) []float64 {
+	widths := make([]float64, len(contents))
+	spec := func(i int) ColumnWidths {
+		if i < len(specs) {
+			return specs[i]
+		}
+		return ColumnWidths{}
+	}
+	left := page
+	var auto, fractions []int
+	for i := range widths {
+		c := spec(i)
+		switch {
+		case c.Points > 0:
+			widths[i] = c.clamp(c.Points)
+		case c.Percent > 0:
+			widths[i] = c.clamp(math.Floor(page * c.Percent / 100))
+		case c.Fraction > 0:
+			fractions = append(fractions, i)
+			continue
+		default:
+			auto = append(auto, i)
+			continue
+		}
+		left -= widths[i]
+	}
+	autoWidths := make([]float64, len(auto))
+	for ai, i := range auto {
+		autoWidths[ai] = contents[i]
+	}
+	if fit {
+		autoWidths = scaleColumns(autoWidths, math.Max(left, 0))
+	} else {
+		autoWidths = fitColumns(autoWidths, math.Max(left, 0))
+	}
+	for ai, i := range auto {
+		widths[i] = spec(i).clamp(autoWidths[ai])
+		left -= widths[i]
+	}
+	for done := make(map[int]bool); len(done) < len(fractions); {
+		weight := 0.0
+		for _, i := range fractions {
+			if !done[i] {
+				weight += spec(i).Fraction
+			}
+		}
+		share := math.Max(left, 0) / weight
+		clamped := false
+		for _, i := range fractions {
+			if w := math.Floor(share * spec(i).Fraction); !done[i] && spec(i).clamp(w) != w {
+				widths[i], done[i] = spec(i).clamp(w), true
+				left -= widths[i]
+				clamped = true
+			}
+		}
+		if !clamped {
+			for _, i := range fractions {
+				if !done[i] {
+					widths[i], done[i] = math.Floor(share*spec(i).Fraction), true
+				}
+			}
+		}
+	}
+	return widths
+}
+
+// scaleColumns returns the widths scaled down alike to fit in width.
+func scaleColumns(widths []float64, width float64) []float64 {
+	total := 0.0
+	for _, w := range widths {
+		total += w
+	}
+	if total <= width {
+		return widths
+	}
+	out := make([]float64, len(widths))
+	for i, w := range widths {
+		out[i] = math.Floor(w * width / total)
+	}
+	return out
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"log"
+	"strings"
+
 	"github.com/braddschick/simpdf"
 	"github.com/braddschick/simpdf/pkg/colors"
 	"github.com/braddschick/simpdf/pkg/defaults"
@@ -16,19 +19,33 @@ func main() {
 	// defaults.Narrow_Margins is the margin information for the document
 	// "" if you want to use custom fonts this will be the custom font directory path
 	// defaults.BasicStyle[5].Font.Name = "courier"
-	pdf.Start("Letter", false, defaults.BasicStyle, defaults.Narrow_Margins, "")
+	if err := pdf.Start("Letter", false, defaults.BasicStyle, defaults.Narrow_Margins, ""); err != nil {
+		log.Fatal(err)
+	}
 	pdf.Details("Testing Functions", "Author", "Testing Subject", "More Keywords, here, here")
-	simpdf.Header.LeftContent.Text = pdf.Title
-	simpdf.Header.LeftContent.Style = defaults.Basic_Table
+	header := simpdf.NewHeaderFooters()
+	// Header and footer text can hold variables such as {title}, {author}, {date:Jan 2, 2006},
+	// {page}, {pages}, {section}, or any registered with pdf.SetVariable()
+	header.LeftContent.Text = "{title} - {section}"
+	header.LeftContent.Style = defaults.Basic_Table
 	bold := defaults.Basic_Table
 	bold.TextVariant.Bold = true
-	simpdf.Header.RightContent.Style = bold
-	simpdf.Header.RightContent.PageNumber = true
-	simpdf.Header.RightContent.Text = "|"
-	pdf.SetHeader()
-	simpdf.Footer.CenterContent.Style = defaults.Basic_Table
-	simpdf.Footer.CenterContent.Text = "CONFIDENTIAL"
-	pdf.SetFooter()
+	header.RightContent.Style = bold
+	header.RightContent.PageNumber = true
+	header.RightContent.Prepend = true
+	header.RightContent.Text = "|"
+	header.RightContent.Numbering = models.PageNumbers{
+		Format:    "Page {page} of {pages}",
+		Separator: models.Borders{Color: colors.Blue600, Width: models.BorderWidths{Bottom: 1}},
+	}
+	// The title page has no header, FirstPage, OddPage, and EvenPage variants are also available
+	header.FirstPage = &simpdf.HeaderFooters{}
+	pdf.SetHeader(header)
+	footer := simpdf.NewHeaderFooters()
+	footer.CenterContent.Style = defaults.Basic_Table
+	pdf.SetVariable("classification", "CONFIDENTIAL")
+	footer.CenterContent.Text = "{classification}"
+	pdf.SetFooter(footer)
 	l := &models.Alignments{Left: true}
 	// pdf.WriteCenter writes in the center of the document great for title pages
 	// "Title" denotes the style of text to be used. This is the name of the models.Style to utilize
@@ -79,6 +96,130 @@ func main() {
 	pdf.AddTable(table, altRow, 140)
 	pdf.Write("", *l, "This is a simple table with _*Ditstribute Evenly column width*_.")
 	pdf.AddTable(table, altRow, pdf.DistributeColumnsEvenly(3))
+	// Cell text wraps within its column, every cell of the row grows to the tallest, and
+	// automatic column widths never exceed the width of the page
+	wrapped := table
+	wrapped.Rows = append(wrapped.Rows, []string{"L**Steamboat Willie, the short film that introduced Mickey Mouse and Minnie Mouse to theater audiences across the country and, in time, the world", "C**1928", "R**n/a"})
+	pdf.Write("", *l, "This is a simple table with _*wrapped cell text*_.")
+	pdf.AddTable(wrapped, altRow, 0)
+	// Cells span columns and rows with Tables.HeaderCells and Tables.Cells
+	spanned := simpdf.Tables{
+		HeaderStyle: headerRow,
+		RowStyle:    defaults.Basic_Table,
+		HeaderCells: [][]simpdf.TableCell{
+			{{Text: "Studio", RowSpan: 2}, {Text: "Premiered", Align: "C", ColSpan: 2}},
+			{simpdf.NewTableCell("C**Character"), simpdf.NewTableCell("C**Year")},
+		},
+		Cells: [][]simpdf.TableCell{
+			{{Text: "Disney", RowSpan: 2}, {Text: "Mickey Mouse"}, {Text: "1928", Align: "C"}},
+			{{Text: "Donald Duck"}, {Text: "1934", Align: "C"}},
+			{{Text: "Fleischer"}, {Text: "Popeye"}, {Text: "1919", Align: "C"}},
+		},
+	}
+	pdf.Write("", *l, "This is a simple table with _*spanned cells*_.")
+	pdf.AddTable(spanned, altRow, 0)
+	// Columns, cells, and conditions have their own styles
+	boldColumn := defaults.Basic_Table
+	boldColumn.Name = "Bold Column"
+	boldColumn.TextVariant.Bold = true
+	negative := defaults.Basic_Table
+	negative.Name = "Negative"
+	negative.Color = colors.Red600
+	styled := table
+	styled.ColumnStyles = []models.Styles{boldColumn}
+	styled.Rows = append(styled.Rows, []string{"L**Goofy", "C**1932", "L**-$  250"})
+	// A condition is given the float64 or time.Time value of a cell of a typed column, and the
+	// Value or else the Text of any other cell, so the type of the value is checked before use
+	styled.Conditions = []simpdf.TableCondition{func(row, col int, value interface{}) models.Styles {
+		if text, ok := value.(string); ok && col == 2 && strings.HasPrefix(text, "-") {
+			return negative
+		}
+		return models.Styles{}
+	}}
+	pdf.Write("", *l, "This is a simple table with _*column and conditional styles*_.")
+	pdf.AddTable(styled, altRow, 0)
+	// Tables can be built from structs, maps, CSV, and database/sql rows
+	type character struct {
+		Name      string  `pdf:"Character"`
+		Premiered int     `pdf:"Premiered,align=C"`
+		Salary    float64 `pdf:"Salary,format=$ %.2f"`
+	}
+	fromStructs, err := simpdf.TableFromStructs([]character{{"Bugs Bunny", 1940, 1500000}, {"Daffy Duck", 1937, 750000.5}})
+	if err != nil {
+		log.Fatal(err)
+	}
+	pdf.Write("", *l, "This is a simple table _*built from structs*_.")
+	pdf.AddTable(fromStructs, altRow, 0)
+	// Footers, totals, and groups with subtotals
+	totals := simpdf.Tables{
+		Headers:     []string{"C**Studio", "C**Character", "C**Salary"},
+		HeaderStyle: headerRow,
+		RowStyle:    defaults.Basic_Table,
+		Rows: [][]string{
+			{"L**Disney", "L**Mickey Mouse", "R**3,000,000.00"},
+			{"L**Fleischer", "L**Popeye", "R**500,000.00"},
+			{"L**Disney", "L**Donald Duck", "R**5,000,000.00"},
+		},
+		Footers:   []string{"L**Total"},
+		Totals:    []models.Aggregates{models.NoAggregate, models.Count, models.Sum},
+		HasGroups: true,
+		GroupBy:   0,
+	}
+	pdf.Write("", *l, "This is a simple table with _*groups, subtotals, and totals*_.")
+	pdf.AddTable(totals, models.Styles{}, 0)
+	// Column widths in pts, percent of the page, or fractions of the width left
+	sized := table
+	if sized.ColumnWidths, err = simpdf.ParseColumnWidths("2fr 80pt 1fr"); err != nil {
+		log.Fatal(err)
+	}
+	pdf.Write("", *l, "This is a simple table with _*2fr 80pt 1fr column widths*_.")
+	pdf.AddTable(sized, altRow, 0)
+	// Cell padding, vertical alignment, and border presets
+	boxed := wrapped
+	boxed.Padding = models.Margins{Left: 6, Top: 3, Right: 6, Bottom: 3}
+	boxed.VerticalAlign = "M"
+	boxed.Borders = models.HorizontalBorders
+	pdf.Write("", *l, "This is a simple table with _*padding, middle alignment, and horizontal borders*_.")
+	pdf.AddTable(boxed, altRow, 0)
+	// Cells hold images, shrunk to fit, and nested tables
+	details := simpdf.Tables{
+		Headers:     []string{"C**Year", "C**Studio"},
+		HeaderStyle: defaults.Basic_Table,
+		RowStyle:    defaults.Basic_Table,
+		Rows:        [][]string{{"C**2009", "L**Google"}},
+	}
+	catalog := simpdf.Tables{
+		Headers:       []string{"C**Mascot", "C**Picture", "C**Details"},
+		HeaderStyle:   headerRow,
+		RowStyle:      defaults.Basic_Table,
+		VerticalAlign: "M",
+		Cells: [][]simpdf.TableCell{
+			{{Text: "Gopher"}, {Align: "C", Image: simpdf.Images{FilePath: "./images/golang.png", Height: 60}}, {Table: &details}},
+		},
+	}
+	pdf.Write("", *l, "This is a simple table with _*an image and a nested table*_.")
+	pdf.AddTable(catalog, altRow, 0)
+	// Typed columns read and write numbers, currency, percentages, and dates in their format
+	ledger := simpdf.Tables{
+		Headers:     []string{"C**Character", "C**Premiered", "C**Salary", "C**Share"},
+		HeaderStyle: headerRow,
+		RowStyle:    defaults.Basic_Table,
+		ColumnFormats: []simpdf.ColumnFormats{
+			{},
+			{Type: models.DateColumn, Layout: "Jan 2006"},
+			{Type: models.CurrencyColumn, Decimals: 2, Thousands: ",", Parentheses: true},
+			{Type: models.PercentColumn, Decimals: 1},
+		},
+		Cells: [][]simpdf.TableCell{
+			{{Text: "Mickey Mouse"}, {Text: "1928-11-18"}, {Value: 3000000}, {Value: 0.6}},
+			{{Text: "Popeye"}, {Text: "1929-01-17"}, {Value: -500000}, {Value: 0.1}},
+			{{Text: "Donald Duck"}, {Text: "1934-06-09"}, {Value: 5000000.5}, {Value: 0.3}},
+		},
+		Footers: []string{"L**Total"},
+		Totals:  []models.Aggregates{models.NoAggregate, models.NoAggregate, models.Sum, models.Sum},
+	}
+	pdf.Write("", *l, "This is a simple table with _*typed columns*_.")
+	pdf.AddTable(ledger, altRow, 0)
 	pdf.Break()
 	pdf.Write("", *l, "Also, tables do not have to have header rows if they are not needed. Just add empty strings to the Tables.Headers string list to ensure the column count is the same.")
 	pdf.Write("", *l, "* Note the first column header __CAN__ be blank if required by your table.")
@@ -93,6 +234,9 @@ func main() {
 	goImage.ChangeWidth(75)
 	pdf.WriteImageInset("Normal", *l, "This needs to have margin left away from the image, but also look decent. _#However#_, I need to ensure it continues with a line break which is why this is so long.", "tl", goImage)
 	pdf.Write("normal", *l, "These two images of the GOpher are the same image. Reuse is easy and _*Images.ChangeHeight()*_ or _*Images.ChangeWidth()*_ can easily change the image as needed.")
-	// pdf.Finish creates the PDF document to the file path listed "./test.pdf"
-	pdf.Finish("./simple_example.pdf")
+	// pdf.Finish creates the PDF document to the file path listed "./simple_example.pdf"
+	// pdf.FinishTo(w) and pdf.Bytes() are available when the document should not go to disk
+	if err := pdf.Finish("./simple_example.pdf"); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,219 @@
+package simpdf
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/braddschick/simpdf/pkg/models"
+)
+
+// tocEntry is a heading written to the PDF document, listed by the table of contents.
+type tocEntry struct {
+	text  string
+	level int
+	page  int
+	link  int
+}
+
+// tableOfContents holds where the table of contents is to be written once all the headings
+// of the PDF document are known.
+type tableOfContents struct {
+	style     string
+	startPage int
+	// tops is the Y coordinate the table of contents starts at on each reserved page.
+	tops []float64
+	// pageTop is the Y coordinate writing starts at on a new page, below the header.
+	pageTop float64
+}
+
+// AddTableOfContents reserves the remainder of the current page for a table of contents
+// written in the style given. See SimPDF.ReserveTableOfContents().
+//
+// Pages cannot be added before those already written, so the headings that follow are not
+// known yet. Write the PDF document with SimPDF.Build() to have as many pages reserved as the
+// table of contents needs, otherwise only the one page is.
+func (s *SimPDF) AddTableOfContents(style string) {
+	s.ReserveTableOfContents(style, 1)
+}
+
+// Build writes the PDF document with build, which starts it with SimPDF.Start() and writes all
+// of its contents. When the table of contents needs more pages than were reserved, by
+// SimPDF.AddTableOfContents() or SimPDF.ReserveTableOfContents(), the document is laid out
+// again from the start by build with as many pages reserved. build should therefore do nothing
+// but write to the SimPDF it is given. The document error, if any, is returned; finish the
+// document with SimPDF.Finish() or the like.
+func (s *SimPDF) Build(build func(pdf *SimPDF)) error {
+	start := *s
+	build(s)
+	if s.Err() || s.toc == nil || start.PDF != nil {
+		return s.Error()
+	}
+	style, ok := s.style(s.toc.style)
+	if !ok {
+		return s.Error()
+	}
+	if pages := s.tocPages(style); pages > len(s.toc.tops) {
+		*s = start
+		s.tocReserve = pages
+		build(s)
+	}
+	return s.Error()
+}
+
+// ReserveTableOfContents reserves the remainder of the current page, and pages-1 pages after
+// it, for a table of contents written in the style given. Writing continues on a new page.
+//
+// The table of contents is written as the PDF document is finished, once every heading is
+// known. It lists each "Title" and "Heading N" written after it, indented by level, with dot
+// leaders, its page number, and a link to the heading. If the headings do not fit in the
+// reserved pages an error wrapping ErrTableOfContentsFull, telling the number of pages
+// needed, is recorded. See SimPDF.Build() to have the pages worked out.
+func (s *SimPDF) ReserveTableOfContents(style string, pages int) {
+	if s.Err() {
+		return
+	}
+	if s.tocReserve > pages {
+		pages = s.tocReserve
+	}
+	if _, ok := s.style(style); !ok {
+		return
+	}
+	if x, _ := s.PDF.GetXY(); x > s.Margin.Left {
+		s.NewLine(0)
+	}
+	s.toc = &tableOfContents{
+		style:     style,
+		startPage: s.PDF.PageNo(),
+		tops:      []float64{s.PDF.GetY()},
+	}
+	for i := 1; i < pages; i++ {
+		s.Break()
+		s.toc.tops = append(s.toc.tops, s.PDF.GetY())
+	}
+	s.Break()
+	s.toc.pageTop = s.PDF.GetY()
+}
+
+// addHeading records a heading, and a link to the current position, for the table of
+// contents.
+func (s *SimPDF) addHeading(text string, level int) {
+	link := s.PDF.AddLink()
+	s.PDF.SetLink(link, -1, -1)
+	s.headings = append(s.headings, tocEntry{text: text, level: level, page: s.PDF.PageNo(), link: link})
+}
+
+// pageNumbering returns the numbering of the first page number in the footer, or else the
+// header, so the table of contents numbers pages the same way.
+func (s *SimPDF) pageNumbering() models.PageNumbers {
+	for _, hf := range []HeaderFooters{s.Footer, s.Header} {
+		for _, c := range hf.allContents() {
+			if c.PageNumber {
+				return c.Numbering
+			}
+		}
+	}
+	return models.PageNumbers{}
+}
+
+// writeTableOfContents writes the table of contents to its reserved pages and then returns
+// to the last page of the PDF document.
+func (s *SimPDF) writeTableOfContents() {
+	toc := s.toc
+	if toc == nil || s.Err() {
+		return
+	}
+	style, ok := s.style(toc.style)
+	if !ok {
+		return
+	}
+	last := s.PDF.PageNo()
+	auto, margin := s.PDF.GetAutoPageBreak()
+	s.PDF.SetAutoPageBreak(false, margin)
+	defer func() {
+		s.PDF.SetPage(last)
+		s.PDF.SetAutoPageBreak(auto, margin)
+	}()
+
+	if pages := s.tocPages(style); pages > len(toc.tops) {
+		s.SetError(fmt.Errorf("%w: needs %d pages, %d reserved", ErrTableOfContentsFull, pages, len(toc.tops)))
+		return
+	}
+	numbering := s.pageNumbering()
+	bottom := s.tocBottom()
+	width := s.Width() - s.Margin.Left - s.Margin.Right
+	cm := s.PDF.GetCellMargin()
+	reserved := 0
+	s.PDF.SetPage(toc.startPage)
+	s.PDF.SetXY(s.Margin.Left, toc.tops[0])
+	s.SetStyle(style, false)
+	for _, e := range s.tocEntries() {
+		if s.PDF.GetY()+style.LineSize > bottom {
+			reserved++
+			s.PDF.SetPage(toc.startPage + reserved)
+			s.PDF.SetXY(s.Margin.Left, toc.tops[reserved])
+			s.SetStyle(style, false)
+		}
+		indent := float64(e.level) * style.TextSize
+		num := numbering.Style.Format(numbering.Number(e.page))
+		numW := s.StringWidth(num) + (2 * cm)
+		text := s.fitText(e.text, width-indent-numW-(2*cm))
+		textW := s.StringWidth(text) + (2 * cm)
+		dotsW := width - indent - textW - numW
+		dots := ""
+		if dotW := s.StringWidth("."); dotW > 0 && dotsW > 2*cm {
+			dots = strings.Repeat(".", int((dotsW-(2*cm))/dotW))
+		}
+		s.PDF.SetX(s.Margin.Left + indent)
+		s.PDF.CellFormat(textW, style.LineSize, text, "", 0, "L", false, e.link, "")
+		s.PDF.CellFormat(dotsW, style.LineSize, dots, "", 0, "R", false, e.link, "")
+		s.PDF.CellFormat(numW, style.LineSize, num, "", 1, "R", false, e.link, "")
+	}
+}
+
+// tocEntries returns the headings listed by the table of contents, those after its pages.
+func (s *SimPDF) tocEntries() []tocEntry {
+	var entries []tocEntry
+	for _, e := range s.headings {
+		if e.page >= s.toc.startPage+len(s.toc.tops) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// tocBottom returns the Y coordinate the table of contents ends at on each page, above the
+// footer.
+func (s *SimPDF) tocBottom() float64 {
+	return s.Height() - math.Max(s.Margin.Bottom, s.footerBand)
+}
+
+// tocPages returns the number of pages the table of contents needs in the style given, a line
+// for each heading.
+func (s *SimPDF) tocPages(style models.Styles) int {
+	bottom := s.tocBottom()
+	pages, y := 1, s.toc.tops[0]
+	for range s.tocEntries() {
+		if y+style.LineSize > bottom {
+			pages++
+			y = s.toc.pageTop
+			if pages <= len(s.toc.tops) {
+				y = s.toc.tops[pages-1]
+			}
+		}
+		y += style.LineSize
+	}
+	return pages
+}
+
+// fitText returns text shortened with "..." so it is no wider than width in the current font.
+func (s *SimPDF) fitText(text string, width float64) string {
+	if s.StringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && s.StringWidth(string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
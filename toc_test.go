@@ -0,0 +1,46 @@
+package simpdf
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/braddschick/simpdf/pkg/defaults"
+	"github.com/braddschick/simpdf/pkg/models"
+)
+
+// writeReport writes a report of the number of headings given with a table of contents.
+func writeReport(pdf *SimPDF, headings int) {
+	if err := pdf.Start("Letter", false, defaults.BasicStyle, defaults.Narrow_Margins, ""); err != nil {
+		return
+	}
+	pdf.AddTableOfContents("Normal")
+	for i := 0; i < headings; i++ {
+		pdf.Write("Heading 1", models.Alignments{Left: true}, fmt.Sprintf("Heading %d", i))
+		pdf.Write("Normal", models.Alignments{Left: true}, "Text of the heading.")
+	}
+}
+
+func TestBuildTableOfContentsPages(t *testing.T) {
+	var pdf SimPDF
+	if err := pdf.Build(func(pdf *SimPDF) { writeReport(pdf, 200) }); err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+	if pages := len(pdf.toc.tops); pages < 2 {
+		t.Errorf("table of contents reserved %d pages, want more than 1", pages)
+	}
+	if got, want := len(pdf.tocEntries()), 200; got != want {
+		t.Errorf("table of contents lists %d headings, want %d", got, want)
+	}
+	if _, err := pdf.Bytes(); err != nil {
+		t.Errorf("Bytes() = %v", err)
+	}
+}
+
+func TestTableOfContentsFull(t *testing.T) {
+	var pdf SimPDF
+	writeReport(&pdf, 200)
+	if _, err := pdf.Bytes(); !errors.Is(err, ErrTableOfContentsFull) {
+		t.Errorf("Bytes() = %v, want ErrTableOfContentsFull", err)
+	}
+}
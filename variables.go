@@ -0,0 +1,73 @@
+package simpdf
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/braddschick/simpdf/pkg/models"
+)
+
+// variableExp matches a "{name}" or "{name:argument}" variable within Contents.Text.
+var variableExp = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_.-]*)(?::([^{}]*))?\}`)
+
+// defaultDateLayout is the layout of "{date}" when no layout is given.
+const defaultDateLayout = "2006-01-02"
+
+// SetVariable registers the variable "{name}" so it is replaced by value wherever it is
+// used in the Text of a header or footer models.Contents. A registered variable replaces a
+// built-in variable of the same name.
+//
+// The built-in variables are "{title}", "{author}", "{subject}", "{keywords}" as set by
+// SimPDF.Details(), "{date}" or "{date:layout}" for the SimPDF.CreationDate in a time.Format()
+// layout, "{page}" and "{pages}" written as the models.Contents.Numbering prescribes, and
+// "{section}" for the text of the last "Title" or "Heading 1" written.
+func (s *SimPDF) SetVariable(name, value string) {
+	s.SetVariableFunc(name, func() string { return value })
+}
+
+// SetVariableFunc registers the variable "{name}" the same as SimPDF.SetVariable() but the
+// value is computed by fn each time a header or footer is written.
+func (s *SimPDF) SetVariableFunc(name string, fn func() string) {
+	if s.variables == nil {
+		s.variables = make(map[string]func() string)
+	}
+	s.variables[strings.ToLower(name)] = fn
+}
+
+// expandVariables returns text with every known variable replaced by its value for the
+// current page. Unknown variables are left as they are.
+func (s *SimPDF) expandVariables(text string, numbering models.PageNumbers) string {
+	return variableExp.ReplaceAllStringFunc(text, func(match string) string {
+		m := variableExp.FindStringSubmatch(match)
+		name, arg := strings.ToLower(m[1]), m[2]
+		if fn, ok := s.variables[name]; ok {
+			return fn()
+		}
+		switch name {
+		case "title":
+			return s.Title
+		case "author":
+			return s.Author
+		case "subject":
+			return s.Subject
+		case "keywords":
+			return s.Keywords
+		case "date":
+			if arg == "" {
+				arg = defaultDateLayout
+			}
+			if s.CreationDate.IsZero() {
+				return time.Now().Local().Format(arg)
+			}
+			return s.CreationDate.Format(arg)
+		case "page":
+			return numbering.Style.Format(numbering.Number(s.PDF.PageNo()))
+		case "pages":
+			return numbering.TotalAlias()
+		case "section":
+			return s.section
+		}
+		return match
+	})
+}